@@ -0,0 +1,66 @@
+package argparse
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Add a subcommand, and return its parser, which has its own groups
+// registered by Register.
+//
+// When parsing, the options of the parent are parsed before the name of
+// the subcommand, which is the first positional argument, and the rest
+// arguments are parsed by the parser of the subcommand.
+//
+// The subcommand inherits the settings of the parent when being added, such as
+// the GNU style, the env, the default group, the panic, the error collection and
+// the width of the help. And the converters and the validators of the parent
+// are consulted if the subcommand doesn't have one.
+//
+// If the subcommand has been added, return it.
+func (p *Parser) AddCommand(name, help string) *Parser {
+	if cmd, ok := p.commands[name]; ok {
+		return cmd
+	}
+
+	cmd := newParser(p.flagSet.Name() + " " + name)
+	cmd.Panic = p.Panic
+	cmd.gnu = p.gnu
+	cmd.collect = p.collect
+	cmd.auto_env = p.auto_env
+	cmd.env_prefix = p.env_prefix
+	cmd.default_group = p.default_group
+	cmd.width = p.width
+	cmd.parent = p
+	cmd.help = help
+
+	p.commands[name] = cmd
+	p.cmdnames = append(p.cmdnames, name)
+	return cmd
+}
+
+// Return the name of the subcommand given by the command line.
+//
+// Return "" if there are no subcommands, or the subcommand is not given.
+func (p *Parser) Command() string {
+	return p.command
+}
+
+// Parse the rest arguments by the subcommand, the name of which is the first
+// positional argument. If it isn't added, fail.
+func (p *Parser) parseCommand() {
+	if len(p.commands) == 0 || p.NArg() == 0 {
+		return
+	}
+
+	name := p.Arg(0)
+	cmd, ok := p.commands[name]
+	if !ok {
+		p.fail(&ParseError{Err: errors.New(fmt.Sprintf("Unknown command: %v", name))})
+		return
+	}
+
+	Debugf("Parsing the command: %v", name)
+	p.command = name
+	cmd.parse(p.Args()[1:])
+}
@@ -0,0 +1,306 @@
+package argparse
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// The hints of the completion given by the tag, complete.
+const (
+	// Complete the value of the option by the file paths.
+	COMPLETE_FILE = "file"
+
+	// Complete the value of the option by the directory paths.
+	COMPLETE_DIR = "dir"
+)
+
+// completion is the information of an option for the completion.
+type completion struct {
+	names  []string // The names with the prefix, such as "--verbose" and "-v"
+	help   string
+	arg    bool     // Whether the option needs an argument
+	values []string // The enum values
+	hint   string   // COMPLETE_FILE or COMPLETE_DIR
+}
+
+// Write the completion script of the shell, which is one of "bash", "zsh"
+// and "fish", into w.
+//
+// The script is built from the registered options, their help and the enum
+// values from the validator, validate_str_array. The subcommands of this
+// parser are included, and the value of the option with the tag,
+// `complete:"file"` or `complete:"dir"`, is completed by the paths.
+func (p *Parser) WriteCompletion(w io.Writer, shell string) error {
+	buf := bytes.NewBuffer(nil)
+	prog := filepath.Base(p.flagSet.Name())
+	switch shell {
+	case "bash":
+		p.writeBash(buf, prog)
+	case "zsh":
+		p.writeZsh(buf, prog)
+	case "fish":
+		p.writeFish(buf, prog)
+	default:
+		return errors.New(fmt.Sprintf("Don't support the shell: %v", shell))
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Return the information of the completion of all the options.
+func (p *Parser) completions() []completion {
+	var cs []completion
+	for _, opt := range p.options {
+		if opt.pos != "" {
+			continue
+		}
+
+		f := p.flagSet.Lookup(opt.name)
+		c := completion{
+			names:  []string{p.flagName(opt.name)},
+			help:   f.Usage,
+			arg:    !isBoolFlag(f),
+			values: p.enumValues(opt.tag),
+			hint:   getFromTag(opt.tag, TAG_COMPLETE, ""),
+		}
+		if opt.short != "" {
+			c.names = append(c.names, "-"+opt.short)
+		}
+		if neg := p.negatedName(opt.name); neg != "" && f.DefValue == "true" {
+			c.names = append(c.names, neg)
+		}
+		cs = append(cs, c)
+	}
+	return cs
+}
+
+// Return the enum values given by the validator, validate_str_array,
+// which are from the inline arguments or the tag, array.
+//
+// Return nil if there is no such validator.
+func (p *Parser) enumValues(tag reflect.StructTag) []string {
+	for _, s := range splitArgs(tag.Get(TAG_VALIDATE)) {
+		name, args := parseValidator(s)
+		if name != "validate_str_array" && name != "str_array" {
+			continue
+		} else if args != nil {
+			return strings.Split(*args, ",")
+		}
+		return strings.Split(tag.Get("array"), ",")
+	}
+	return nil
+}
+
+// Return the name of the shell function for the program.
+func funcName(prog string) string {
+	return "_" + strings.Map(func(r rune) rune {
+		if ('0' <= r && r <= '9') || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') {
+			return r
+		}
+		return '_'
+	}, prog)
+}
+
+func (p *Parser) writeBash(w io.Writer, prog string) {
+	fn := funcName(prog)
+	fmt.Fprintf(w, "# bash completion for %s\n\n", prog)
+	fmt.Fprintf(w, "%s() {\n", fn)
+	fmt.Fprintf(w, "    local cur=\"${COMP_WORDS[COMP_CWORD]}\" prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n")
+	fmt.Fprintf(w, "    local cmd=\"\" opts=\"\" i\n")
+	if len(p.cmdnames) > 0 {
+		fmt.Fprintf(w, "    for ((i=1; i<COMP_CWORD; i++)); do\n")
+		fmt.Fprintf(w, "        case \"${COMP_WORDS[i]}\" in\n")
+		fmt.Fprintf(w, "            %s) cmd=\"${COMP_WORDS[i]}\"; break ;;\n", strings.Join(p.cmdnames, "|"))
+		fmt.Fprintf(w, "        esac\n")
+		fmt.Fprintf(w, "    done\n")
+	}
+
+	fmt.Fprintf(w, "\n    case \"$cmd\" in\n")
+	for _, name := range p.cmdnames {
+		fmt.Fprintf(w, "    %s)\n", name)
+		p.commands[name].writeBashOptions(w, nil)
+		fmt.Fprintf(w, "        ;;\n")
+	}
+	fmt.Fprintf(w, "    *)\n")
+	p.writeBashOptions(w, p.cmdnames)
+	fmt.Fprintf(w, "        ;;\n")
+	fmt.Fprintf(w, "    esac\n\n")
+
+	fmt.Fprintf(w, "    COMPREPLY=($(compgen -W \"$opts\" -- \"$cur\"))\n")
+	fmt.Fprintf(w, "}\n\n")
+	fmt.Fprintf(w, "complete -F %s %s\n", fn, prog)
+}
+
+func (p *Parser) writeBashOptions(w io.Writer, words []string) {
+	cs := p.completions()
+	for _, c := range cs {
+		words = append(words, c.names...)
+	}
+	fmt.Fprintf(w, "        opts=\"%s\"\n", strings.Join(words, " "))
+
+	fmt.Fprintf(w, "        case \"$prev\" in\n")
+	for _, c := range cs {
+		if !c.arg {
+			continue
+		}
+
+		var reply string
+		switch {
+		case len(c.values) > 0:
+			reply = fmt.Sprintf("COMPREPLY=($(compgen -W \"%s\" -- \"$cur\"))", strings.Join(c.values, " "))
+		case c.hint == COMPLETE_FILE:
+			reply = "COMPREPLY=($(compgen -f -- \"$cur\"))"
+		case c.hint == COMPLETE_DIR:
+			reply = "COMPREPLY=($(compgen -d -- \"$cur\"))"
+		default:
+			reply = "COMPREPLY=()"
+		}
+		fmt.Fprintf(w, "            %s) %s; return ;;\n", strings.Join(c.names, "|"), reply)
+	}
+	fmt.Fprintf(w, "        esac\n")
+}
+
+// Escape the string in the single quotes of zsh.
+func zshQuote(s string) string {
+	s = strings.Replace(s, "\n", " ", -1)
+	return strings.Replace(s, "'", `'\''`, -1)
+}
+
+// Escape the description of the option of _arguments in the single quotes of zsh.
+func zshDesc(s string) string {
+	s = strings.Replace(zshQuote(s), "[", `\[`, -1)
+	s = strings.Replace(s, "]", `\]`, -1)
+	return strings.Replace(s, ":", `\:`, -1)
+}
+
+func (p *Parser) writeZsh(w io.Writer, prog string) {
+	fn := funcName(prog)
+	fmt.Fprintf(w, "#compdef %s\n\n", prog)
+	fmt.Fprintf(w, "%s() {\n", fn)
+	fmt.Fprintf(w, "    local state line\n")
+	fmt.Fprintf(w, "    _arguments -C \\\n")
+	p.writeZshOptions(w, "        ")
+	if len(p.cmdnames) > 0 {
+		fmt.Fprintf(w, "        '1: :->command' \\\n")
+		fmt.Fprintf(w, "        '*:: :->args'\n\n")
+
+		fmt.Fprintf(w, "    case $state in\n")
+		fmt.Fprintf(w, "    command)\n")
+		fmt.Fprintf(w, "        local -a commands\n")
+		fmt.Fprintf(w, "        commands=(\n")
+		for _, name := range p.cmdnames {
+			fmt.Fprintf(w, "            '%s:%s'\n", name, zshQuote(p.commands[name].help))
+		}
+		fmt.Fprintf(w, "        )\n")
+		fmt.Fprintf(w, "        _describe 'command' commands\n")
+		fmt.Fprintf(w, "        ;;\n")
+		fmt.Fprintf(w, "    args)\n")
+		fmt.Fprintf(w, "        case $line[1] in\n")
+		for _, name := range p.cmdnames {
+			fmt.Fprintf(w, "        %s)\n", name)
+			fmt.Fprintf(w, "            _arguments \\\n")
+			p.commands[name].writeZshOptions(w, "                ")
+			fmt.Fprintf(w, "                '*: :_files'\n")
+			fmt.Fprintf(w, "            ;;\n")
+		}
+		fmt.Fprintf(w, "        esac\n")
+		fmt.Fprintf(w, "        ;;\n")
+		fmt.Fprintf(w, "    esac\n")
+	} else {
+		fmt.Fprintf(w, "        '*: :_files'\n")
+	}
+	fmt.Fprintf(w, "}\n\n")
+	fmt.Fprintf(w, "%s \"$@\"\n", fn)
+}
+
+func (p *Parser) writeZshOptions(w io.Writer, indent string) {
+	for _, c := range p.completions() {
+		var action string
+		if c.arg {
+			switch {
+			case len(c.values) > 0:
+				action = fmt.Sprintf(":value:(%s)", strings.Join(c.values, " "))
+			case c.hint == COMPLETE_FILE:
+				action = ":file:_files"
+			case c.hint == COMPLETE_DIR:
+				action = ":directory:_files -/"
+			default:
+				action = ":value: "
+			}
+		}
+
+		for _, name := range c.names {
+			fmt.Fprintf(w, "%s'%s[%s]%s' \\\n", indent, name, zshDesc(c.help), action)
+		}
+	}
+}
+
+// Quote the string by the single quotes of fish.
+func fishQuote(s string) string {
+	s = strings.Replace(s, "\n", " ", -1)
+	s = strings.Replace(s, `\`, `\\`, -1)
+	return "'" + strings.Replace(s, "'", `\'`, -1) + "'"
+}
+
+func (p *Parser) writeFish(w io.Writer, prog string) {
+	fmt.Fprintf(w, "# fish completion for %s\n\n", prog)
+	if len(p.cmdnames) == 0 {
+		p.writeFishOptions(w, prog, "")
+		return
+	}
+
+	p.writeFishOptions(w, prog, "__fish_use_subcommand")
+	for _, name := range p.cmdnames {
+		fmt.Fprintf(w, "complete -c %s -f -n __fish_use_subcommand -a %s -d %s\n", prog, name,
+			fishQuote(p.commands[name].help))
+	}
+	for _, name := range p.cmdnames {
+		condition := "'__fish_seen_subcommand_from " + name + "'"
+		p.commands[name].writeFishOptions(w, prog, condition)
+	}
+}
+
+func (p *Parser) writeFishOptions(w io.Writer, prog, condition string) {
+	for _, c := range p.completions() {
+		var args []string
+		if condition != "" {
+			args = append(args, "-n", condition)
+		}
+
+		for _, name := range c.names {
+			switch {
+			case strings.HasPrefix(name, "--"):
+				args = append(args, "-l", name[2:])
+			case len(name) == 2:
+				args = append(args, "-s", name[1:])
+			default:
+				args = append(args, "-o", name[1:])
+			}
+		}
+
+		if c.help != "" {
+			args = append(args, "-d", fishQuote(c.help))
+		}
+
+		if c.arg {
+			switch {
+			case len(c.values) > 0:
+				args = append(args, "-r", "-f", "-a", fishQuote(strings.Join(c.values, " ")))
+			case c.hint == COMPLETE_FILE:
+				args = append(args, "-r", "-F")
+			case c.hint == COMPLETE_DIR:
+				args = append(args, "-r", "-f", "-a", "'(__fish_complete_directories)'")
+			default:
+				args = append(args, "-r", "-f")
+			}
+		}
+
+		fmt.Fprintf(w, "complete -c %s %s\n", prog, strings.Join(args, " "))
+	}
+}
@@ -0,0 +1,211 @@
+package argparse
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Load the values of the options from the configuration file, the format of
+// which is decided by the extension of the file. If it's ".json", it's JSON.
+// Or it's INI.
+//
+// It must be called after registering and before parsing. The value from
+// the file overrides the default value given by the tag, but is overridden by
+// the environment variable and the command line.
+//
+// Return an error if failing to read or parse the file, or if there is
+// an unknown option in the file.
+func (p *Parser) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		err = p.parseJSON(f, path)
+	} else {
+		err = p.parseINI(f, path)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%v: %w", path, err)
+	}
+	return nil
+}
+
+// Load the values of the options from the JSON.
+//
+// The top-level keys are the options of the default group, and the objects
+// are the groups, which can be nested for the sub-groups, such as
+//
+//	{"str": "0.0.0.0", "group": {"str": "127.0.0.1", "db": {"host": "10.0.0.1"}}}
+//
+// The array is the value of the slice option, and the object is the value of
+// the map option. See LoadFile.
+func (p *Parser) LoadJSON(r io.Reader) error {
+	return p.parseJSON(r, "")
+}
+
+// Load the JSON from the file, the path of which is where, or "" if unknown.
+func (p *Parser) parseJSON(r io.Reader, where string) error {
+	var values map[string]interface{}
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	if err := decoder.Decode(&values); err != nil {
+		return err
+	}
+
+	var names []string
+	p.setSource(SOURCE_FILE, where)
+	if err := p.loadJSON(p.default_group, values, &names); err != nil {
+		return err
+	}
+	p.resetOptions(names)
+	return nil
+}
+
+func (p *Parser) loadJSON(gname string, values map[string]interface{}, names *[]string) error {
+	for _, key := range sortedKeys(values) {
+		name := p.getName(gname, key)
+		value := values[key]
+
+		// The object which is not an option is the group.
+		if object, ok := value.(map[string]interface{}); ok {
+			if _, ok := p.group[name]; !ok {
+				if err := p.loadJSON(name, object, names); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		if _, ok := p.group[name]; !ok {
+			return errors.New(fmt.Sprintf("Unknown option: %v", name))
+		}
+
+		var vs []string
+		switch v := value.(type) {
+		case nil:
+			continue
+		case []interface{}:
+			for _, e := range v {
+				vs = append(vs, fmt.Sprintf("%v", e))
+			}
+		case map[string]interface{}:
+			for _, k := range sortedKeys(v) {
+				vs = append(vs, fmt.Sprintf("%v=%v", k, v[k]))
+			}
+		default:
+			vs = []string{fmt.Sprintf("%v", v)}
+		}
+
+		for _, v := range vs {
+			if err := p.setFromFile(name, v); err != nil {
+				return err
+			}
+		}
+		*names = append(*names, name)
+	}
+	return nil
+}
+
+// Load the values of the options from the INI.
+//
+// The keys before any section are the options of the default group,
+// and the sections are the groups. The sub-group is joined by the dot,
+// such as "[server.db]". The line starting with ";" or "#" is the comment.
+// If a key appears more than once, the value is appended to the slice option.
+// See LoadFile.
+func (p *Parser) LoadINI(r io.Reader) error {
+	return p.parseINI(r, "")
+}
+
+// Load the INI from the file, the path of which is where, or "" if unknown.
+func (p *Parser) parseINI(r io.Reader, where string) error {
+	var names []string
+	gname := p.default_group
+	p.setSource(SOURCE_FILE, where)
+	scanner := bufio.NewScanner(r)
+	for lineno := 1; scanner.Scan(); lineno++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+
+		if line[0] == '[' {
+			if line[len(line)-1] != ']' {
+				return errors.New(fmt.Sprintf("line %v: the section is invalid", lineno))
+			}
+
+			gname = p.default_group
+			for _, s := range strings.Split(line[1:len(line)-1], ".") {
+				gname = p.getName(gname, strings.TrimSpace(s))
+			}
+			continue
+		}
+
+		index := strings.Index(line, "=")
+		if index < 1 {
+			return errors.New(fmt.Sprintf("line %v: the format is not key=value", lineno))
+		}
+
+		name := p.getName(gname, strings.TrimSpace(line[:index]))
+		if _, ok := p.group[name]; !ok {
+			return errors.New(fmt.Sprintf("line %v: unknown option: %v", lineno, name))
+		}
+
+		value := strings.TrimSpace(line[index+1:])
+		if len(value) > 1 && value[0] == '"' && value[len(value)-1] == '"' {
+			value = value[1 : len(value)-1]
+		}
+
+		if err := p.setFromFile(name, value); err != nil {
+			return fmt.Errorf("line %v: %w", lineno, err)
+		}
+		names = append(names, name)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	p.resetOptions(names)
+	return nil
+}
+
+func (p *Parser) setFromFile(name, value string) error {
+	Debugf("Setting the option[%v] by the configuration file", name)
+	if err := p.flagSet.Set(name, value); err != nil {
+		if p.isSecret(name) {
+			value = secretMask
+		}
+		return &ParseError{Option: name, Value: value, Err: err}
+	}
+	return nil
+}
+
+// Make the next source replace the values of the repeatable options, not append to them.
+func (p *Parser) resetOptions(names []string) {
+	for _, name := range names {
+		if r, ok := p.group[name].(resetter); ok {
+			r.reset()
+		}
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
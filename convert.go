@@ -0,0 +1,82 @@
+package argparse
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var converters = make(tConverter)
+
+// Register a converter to convert the string to the value of the type, typ.
+//
+// The value returned by the converter must be the type of typ, or can be
+// converted to it. When registering or parsing the options, the converters
+// are consulted before the built-in types.
+//
+// Return true if registering successfully. Return false if having been registered.
+// If the converter is nil, it will panic.
+func RegisterConverter(typ reflect.Type, converter func(string) (interface{}, error)) bool {
+	return converters.register(typ, converter)
+}
+
+// Register a converter only for this parser, which shadows the global one
+// registered by RegisterConverter. Must register it before registering
+// the options. See RegisterConverter.
+func (p *Parser) RegisterConverter(typ reflect.Type, converter func(string) (interface{}, error)) bool {
+	return p.converters.register(typ, converter)
+}
+
+type tConverter map[reflect.Type]func(string) (interface{}, error)
+
+func (t tConverter) register(typ reflect.Type, converter func(string) (interface{}, error)) bool {
+	if _, ok := t[typ]; ok {
+		return false
+	}
+
+	if converter == nil {
+		panic("The converter is invalid")
+	}
+
+	t[typ] = converter
+	return true
+}
+
+func (p *Parser) getConverter(typ reflect.Type) func(string) (interface{}, error) {
+	for ; p != nil; p = p.parent {
+		if converter, ok := p.converters[typ]; ok {
+			return converter
+		}
+	}
+	return converters[typ]
+}
+
+// Return true if the type can be converted by the converters or the built-in way.
+func (p *Parser) canConvert(typ reflect.Type) bool {
+	return p.getConverter(typ) != nil || canConvert(typ)
+}
+
+// Convert the string to the value of the type, typ, by the converters,
+// or by the built-in way if there is no converter for the type.
+func (p *Parser) convert(typ reflect.Type, s string) (reflect.Value, error) {
+	converter := p.getConverter(typ)
+	if converter == nil {
+		return convertValue(typ, s)
+	}
+
+	v, err := converter(s)
+	if err != nil {
+		return reflect.Zero(typ), err
+	} else if v == nil {
+		return reflect.Zero(typ), nil
+	}
+
+	value := reflect.ValueOf(v)
+	if value.Type() == typ {
+		return value, nil
+	} else if value.Type().ConvertibleTo(typ) {
+		return value.Convert(typ), nil
+	}
+	return reflect.Zero(typ), errors.New(fmt.Sprintf("The converter returns %v, not %v",
+		value.Type(), typ))
+}
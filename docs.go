@@ -0,0 +1,190 @@
+package argparse
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// Write the man page in roff into w, which is built from the registered groups,
+// options, defaults, validators and the subcommands like the help.
+//
+// The header of the help is the description, and the footer is the notes.
+// See WriteHelp.
+func (p *Parser) WriteManPage(w io.Writer) error {
+	buf := bytes.NewBuffer(nil)
+	prog := filepath.Base(p.flagSet.Name())
+
+	fmt.Fprintf(buf, ".TH %s 1\n", roffEscape(strings.ToUpper(prog)))
+	fmt.Fprintf(buf, ".SH NAME\n%s\n", roffEscape(prog))
+	fmt.Fprintf(buf, ".SH SYNOPSIS\n.B %s\n%s\n", roffEscape(prog), roffEscape(p.synopsis()))
+	if p.header != "" {
+		fmt.Fprintf(buf, ".SH DESCRIPTION\n%s\n", roffText(p.header))
+	}
+
+	if len(p.cmdnames) == 0 && len(p.positionals()) > 0 {
+		fmt.Fprintf(buf, ".SH ARGUMENTS\n")
+		p.writeManArguments(buf)
+	}
+
+	if len(p.options) > 0 {
+		fmt.Fprintf(buf, ".SH OPTIONS\n")
+		p.writeManOptions(buf, true)
+	}
+
+	if len(p.cmdnames) > 0 {
+		fmt.Fprintf(buf, ".SH COMMANDS\n")
+		for _, name := range p.cmdnames {
+			cmd := p.commands[name]
+			fmt.Fprintf(buf, ".SS %s\n", roffEscape(name))
+			if cmd.help != "" {
+				fmt.Fprintf(buf, "%s\n", roffText(cmd.help))
+			}
+			fmt.Fprintf(buf, ".PP\n.B %s %s\n%s\n", roffEscape(prog), roffEscape(name),
+				roffEscape(cmd.synopsis()))
+			if len(cmd.cmdnames) == 0 {
+				cmd.writeManArguments(buf)
+			}
+			cmd.writeManOptions(buf, false)
+		}
+	}
+
+	if p.footer != "" {
+		fmt.Fprintf(buf, ".SH NOTES\n.nf\n%s\n.fi\n", roffText(p.footer))
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Write the positional arguments in roff.
+func (p *Parser) writeManArguments(w io.Writer) {
+	for _, opt := range p.positionals() {
+		fmt.Fprintf(w, ".TP\n.B %s\n", roffEscape(opt.metavar))
+		if desc := p.describe(opt); desc != "" {
+			fmt.Fprintf(w, "%s\n", roffText(desc))
+		}
+	}
+}
+
+// Write the options in roff. If heading is true, each group has a heading.
+func (p *Parser) writeManOptions(w io.Writer, heading bool) {
+	gnames, groups := p.optionGroups()
+	for _, gname := range gnames {
+		if heading {
+			fmt.Fprintf(w, ".SS %s\n", roffEscape(gname))
+			if desc := p.gdescs[gname]; desc != "" {
+				fmt.Fprintf(w, "%s\n", roffText(desc))
+			}
+		}
+
+		for _, opt := range groups[gname] {
+			fmt.Fprintf(w, ".TP\n.B %s\n", roffEscape(p.optionNames(opt)))
+			if typ := p.optionType(opt); typ != "" {
+				fmt.Fprintf(w, ".I %s\n", roffEscape(typ))
+			}
+			if desc := p.describe(opt); desc != "" {
+				fmt.Fprintf(w, ".br\n%s\n", roffText(desc))
+			}
+		}
+	}
+}
+
+// Write the reference of the options in Markdown into w, which is built from
+// the registered groups, options, defaults, validators and the subcommands
+// like the help. Each subcommand has its own section. See WriteHelp.
+func (p *Parser) WriteMarkdown(w io.Writer) error {
+	buf := bytes.NewBuffer(nil)
+	p.writeMarkdown(buf, "#")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func (p *Parser) writeMarkdown(w io.Writer, level string) {
+	prog := filepath.Base(p.flagSet.Name())
+	fmt.Fprintf(w, "%s %s\n\n", level, prog)
+	if p.header != "" {
+		fmt.Fprintf(w, "%s\n\n", strings.TrimRight(p.header, "\n"))
+	} else if p.help != "" {
+		fmt.Fprintf(w, "%s\n\n", strings.TrimRight(p.help, "\n"))
+	}
+	fmt.Fprintf(w, "```\n%s %s\n```\n\n", prog, p.synopsis())
+
+	if len(p.cmdnames) == 0 && len(p.positionals()) > 0 {
+		fmt.Fprintf(w, "%s# Arguments\n\n", level)
+		fmt.Fprintf(w, "| Argument | Type | Description |\n|---|---|---|\n")
+		for _, opt := range p.positionals() {
+			fmt.Fprintf(w, "| `%s` | %s | %s |\n", opt.metavar, markdownCell(typeName(opt.typ)),
+				markdownCell(p.describe(opt)))
+		}
+		fmt.Fprintf(w, "\n")
+	}
+
+	gnames, groups := p.optionGroups()
+	for _, gname := range gnames {
+		fmt.Fprintf(w, "%s# %s\n\n", level, gname)
+		if desc := p.gdescs[gname]; desc != "" {
+			fmt.Fprintf(w, "%s\n\n", desc)
+		}
+
+		fmt.Fprintf(w, "| Option | Type | Description |\n|---|---|---|\n")
+		for _, opt := range groups[gname] {
+			names := strings.Split(p.optionNames(opt), ", ")
+			fmt.Fprintf(w, "| `%s` | %s | %s |\n", strings.Join(names, "`, `"),
+				markdownCell(p.optionType(opt)), markdownCell(p.describe(opt)))
+		}
+		fmt.Fprintf(w, "\n")
+	}
+
+	if p.footer != "" {
+		fmt.Fprintf(w, "%s\n\n", strings.TrimRight(p.footer, "\n"))
+	}
+
+	if len(p.cmdnames) > 0 {
+		fmt.Fprintf(w, "%s# Commands\n\n", level)
+		fmt.Fprintf(w, "| Command | Description |\n|---|---|\n")
+		for _, name := range p.cmdnames {
+			fmt.Fprintf(w, "| `%s` | %s |\n", name, markdownCell(p.commands[name].help))
+		}
+		fmt.Fprintf(w, "\n")
+
+		for _, name := range p.cmdnames {
+			p.commands[name].writeMarkdown(w, level+"#")
+		}
+	}
+}
+
+// Return the arguments of the usage line, such as "[OPTIONS] SOURCE [DEST]".
+func (p *Parser) synopsis() string {
+	if len(p.cmdnames) > 0 {
+		return "[OPTIONS] COMMAND"
+	} else if positional := p.positionalUsage(); positional != "" {
+		return "[OPTIONS] " + positional
+	}
+	return "[OPTIONS]"
+}
+
+// Escape the text in a line of roff.
+func roffEscape(s string) string {
+	s = strings.Replace(s, `\`, `\e`, -1)
+	s = strings.Replace(s, "-", `\-`, -1)
+	return strings.Replace(s, "\n", " ", -1)
+}
+
+// Escape the text of roff, which may have multiple lines, each of which is not
+// interpreted as the request even if starting with "." or "'".
+func roffText(s string) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = `\&` + roffEscape(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Escape the text in the cell of the Markdown table.
+func markdownCell(s string) string {
+	s = strings.Replace(s, "|", `\|`, -1)
+	return strings.Replace(s, "\n", "<br>", -1)
+}
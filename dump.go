@@ -0,0 +1,230 @@
+package argparse
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Write the current values of all the options into w in the format, which is
+// one of "flag", "env", "json" and "ini", so that it can be given back by
+// the command line, the environment variables, LoadJSON and LoadINI.
+// It's used to print the effective configuration after parsing.
+//
+// For "env", the option without the environment variable is skipped, see
+// SetAutoEnv. The positional arguments are not included, and the value of
+// the option with the strategy, secret, is masked.
+func (p *Parser) Dump(w io.Writer, format string) error {
+	buf := bytes.NewBuffer(nil)
+	switch format {
+	case "flag":
+		p.dumpFlag(buf)
+	case "env":
+		p.dumpEnv(buf)
+	case "json":
+		p.dumpJSON(buf)
+	case "ini":
+		p.dumpINI(buf)
+	default:
+		return errors.New(fmt.Sprintf("Don't support the format: %v", format))
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Return the current values of the option as the strings. The repeatable
+// option has one string per element, and the others have only one.
+func (p *Parser) dumpValues(opt *option) []string {
+	var vs []string
+	if l, ok := p.group[opt.name].(lister); ok {
+		vs = l.list()
+	} else {
+		vs = []string{p.flagSet.Lookup(opt.name).Value.String()}
+	}
+
+	if hasStrategy(opt.tag, STRATEGY_SECRET) {
+		for i := range vs {
+			vs[i] = secretMask
+		}
+	}
+	return vs
+}
+
+// Return the groups in the order of registering, the default group first,
+// and the options of each group.
+func (p *Parser) dumpGroups() ([]string, map[string][]*option) {
+	gnames, groups := p.optionGroups()
+	for i, gname := range gnames {
+		if gname == p.default_group && i > 0 {
+			copy(gnames[1:i+1], gnames[:i])
+			gnames[0] = gname
+		}
+	}
+	return gnames, groups
+}
+
+// Return the key of the option in the group, that's, the name of the option
+// without the name of the group.
+func (p *Parser) dumpKey(opt *option) string {
+	if opt.group == p.default_group {
+		return opt.name
+	}
+	return strings.TrimPrefix(opt.name, strings.ToLower(opt.group)+Sep)
+}
+
+func (p *Parser) dumpFlag(w io.Writer) {
+	var args []string
+	for _, opt := range p.options {
+		if opt.pos != "" {
+			continue
+		}
+		// In the GNU style, the single-letter option is also given by "--",
+		// because the value of "-x=7" is "=7".
+		name := p.flagName(opt.name)
+		if p.gnu {
+			name = "--" + opt.name
+		}
+
+		for _, v := range p.dumpValues(opt) {
+			args = append(args, shellQuote(name+"="+v))
+		}
+	}
+	fmt.Fprintf(w, "%s\n", strings.Join(args, " "))
+}
+
+func (p *Parser) dumpEnv(w io.Writer) {
+	for _, opt := range p.options {
+		env := p.getEnvName(opt)
+		if env == "" || opt.pos != "" {
+			continue
+		}
+
+		// The repeatable option is given by one environment variable,
+		// so its values are joined by the tag, sep.
+		vs := p.dumpValues(opt)
+		if _, ok := p.group[opt.name].(lister); !ok {
+			fmt.Fprintf(w, "%s=%s\n", env, shellQuote(vs[0]))
+		} else if sep := opt.tag.Get(TAG_SEP); sep != "" || len(vs) < 2 {
+			fmt.Fprintf(w, "%s=%s\n", env, shellQuote(strings.Join(vs, sep)))
+		} else {
+			fmt.Fprintf(w, "# %s: the values can't be given by one variable without the tag, sep\n", env)
+		}
+	}
+}
+
+func (p *Parser) dumpJSON(w io.Writer) {
+	gnames, groups := p.dumpGroups()
+	fmt.Fprintf(w, "{")
+	first := true
+	for _, gname := range gnames {
+		indent := "  "
+		if gname != p.default_group {
+			if !first {
+				fmt.Fprintf(w, ",")
+			}
+			fmt.Fprintf(w, "\n  %s: {", jsonString(strings.ToLower(gname)))
+			indent, first = "    ", true
+		}
+
+		for _, opt := range groups[gname] {
+			if !first {
+				fmt.Fprintf(w, ",")
+			}
+			fmt.Fprintf(w, "\n%s%s: %s", indent, jsonString(p.dumpKey(opt)), p.jsonValue(opt))
+			first = false
+		}
+
+		if gname != p.default_group {
+			fmt.Fprintf(w, "\n  }")
+			first = false
+		}
+	}
+	fmt.Fprintf(w, "\n}\n")
+}
+
+// Return the value of the option in JSON, which is an array for the slice,
+// and an object for the map.
+func (p *Parser) jsonValue(opt *option) string {
+	vs := p.dumpValues(opt)
+	if _, ok := p.group[opt.name].(lister); !ok {
+		return jsonScalar(opt.typ, vs[0])
+	}
+
+	ss := make([]string, len(vs))
+	if opt.typ.Kind() == reflect.Map {
+		for i, v := range vs {
+			kv := strings.SplitN(v, "=", 2)
+			ss[i] = jsonString(kv[0]) + ": " + jsonScalar(opt.typ.Elem(), kv[len(kv)-1])
+		}
+		return "{" + strings.Join(ss, ", ") + "}"
+	}
+
+	for i, v := range vs {
+		ss[i] = jsonScalar(opt.typ.Elem(), v)
+	}
+	return "[" + strings.Join(ss, ", ") + "]"
+}
+
+// Return the JSON of the value, which is the bool or the number if the type is,
+// or the string.
+func jsonScalar(typ reflect.Type, s string) string {
+	if typ != durationType {
+		switch typ.Kind() {
+		case reflect.Bool, reflect.Float32, reflect.Float64,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if json.Valid([]byte(s)) {
+				return s
+			}
+		}
+	}
+	return jsonString(s)
+}
+
+func jsonString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+func (p *Parser) dumpINI(w io.Writer) {
+	gnames, groups := p.dumpGroups()
+	for i, gname := range gnames {
+		if gname != p.default_group {
+			if i > 0 {
+				fmt.Fprintf(w, "\n")
+			}
+			fmt.Fprintf(w, "[%s]\n", strings.ToLower(gname))
+		}
+
+		for _, opt := range groups[gname] {
+			for _, v := range p.dumpValues(opt) {
+				fmt.Fprintf(w, "%s\n", strings.TrimRight(p.dumpKey(opt)+" = "+iniQuote(v), " "))
+			}
+		}
+	}
+}
+
+// Quote the value of INI by the double quotes if it has the leading or
+// trailing spaces, or is quoted, which are removed by LoadINI.
+func iniQuote(s string) string {
+	if strings.TrimSpace(s) != s || (len(s) > 1 && s[0] == '"' && s[len(s)-1] == '"') {
+		return `"` + s + `"`
+	}
+	return s
+}
+
+// Quote the string by the single quotes of the shell if necessary.
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(('0' <= r && r <= '9') || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') ||
+			strings.ContainsRune("-_=.,:/@%+", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
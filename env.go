@@ -0,0 +1,72 @@
+package argparse
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Set whether to derive the names of the environment variables of the options
+// automatically, which are the upper names of the options prefixed by the env
+// prefix, such as "APP_GROUP_STR". The default is false, that's, only the
+// options with the tag, env, are read from the environment variables.
+func (p *Parser) SetAutoEnv(auto bool) *Parser {
+	p.auto_env = auto
+	return p
+}
+
+// Set the prefix of the names of the environment variables derived automatically,
+// which is joined with the name of the option by the underscore. The default is "".
+func (p *Parser) SetEnvPrefix(prefix string) *Parser {
+	p.env_prefix = prefix
+	return p
+}
+
+// Return the name of the environment variable of the option.
+//
+// Return "" if the option has no environment variable.
+func (p *Parser) getEnvName(opt *option) string {
+	if opt.env != "" {
+		return opt.env
+	} else if !p.auto_env {
+		return ""
+	}
+
+	name := opt.name
+	if p.env_prefix != "" {
+		name = p.env_prefix + "_" + name
+	}
+	return strings.ToUpper(strings.Map(func(r rune) rune {
+		if r == '_' || ('0' <= r && r <= '9') || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') {
+			return r
+		}
+		return '_'
+	}, name))
+}
+
+// Set the options by the environment variables, which must be called before
+// parsing the command line, so that the latter overrides the former.
+func (p *Parser) parseEnv() {
+	for _, opt := range p.options {
+		env := p.getEnvName(opt)
+		if env == "" || opt.pos != "" {
+			continue
+		}
+
+		value, ok := os.LookupEnv(env)
+		if !ok {
+			continue
+		}
+
+		Debugf("Setting the option[%v] by the environment variable[%v]", opt.name, env)
+		p.setSource(SOURCE_ENV, env)
+		if err := p.flagSet.Set(opt.name, value); err != nil {
+			p.fail(&ParseError{Option: opt.name, Value: fmt.Sprint(maskValue(opt.tag, value)),
+				Err: fmt.Errorf("the environment variable[%v]: %w", env, err)})
+			continue
+		}
+
+		// The command line will replace the value, not append to it.
+		p.resetOptions([]string{opt.name})
+	}
+}
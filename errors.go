@@ -0,0 +1,139 @@
+package argparse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError is the error that the value of the option, or the group,
+// fails to validate.
+//
+// If the group fails to validate, Field and Option are empty, Value is
+// the pointer to the struct of the group, and Validator is "Validate"
+// or "RegisterGroupValidator".
+type ValidationError struct {
+	Group     string      // The name of the group
+	Field     string      // The name of the field
+	Option    string      // The name of the option
+	Validator string      // The name of the validator
+	Value     interface{} // The value of the option
+	Err       error       // The error returned by the validator
+}
+
+func (e *ValidationError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("Failed to validate the group[%v]: [%v] %v", e.Group,
+			e.Validator, e.Err)
+	}
+	return fmt.Sprintf("Failed to validate the field[%v.%v]: [%v] %v", e.Group, e.Field,
+		e.Validator, e.Err)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ParseError is the error that fails to parse the arguments, or to set
+// the option by the value from the command line, the environment variable
+// or the configuration file.
+type ParseError struct {
+	Option string // The name of the option, which is empty if unknown
+	Value  string // The value of the option
+	Err    error  // The cause
+}
+
+func (e *ParseError) Error() string {
+	if e.Option == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("Failed to parse the option[%v] with the value[%v]: %v", e.Option,
+		e.Value, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// RequiredError is the error that the required options are missing.
+type RequiredError struct {
+	Options []string // The names of the missing options, such as "-port" or "SOURCE"
+}
+
+func (e *RequiredError) Error() string {
+	return fmt.Sprintf("Missing the required options: %v", strings.Join(e.Options, ", "))
+}
+
+// ConstraintError is the error that the options violate the set given by
+// the tag, exclusive or together.
+type ConstraintError struct {
+	Constraint string   // TAG_EXCLUSIVE or TAG_TOGETHER
+	Set        string   // The name of the set
+	Options    []string // The given options for exclusive, or the missing ones for together
+}
+
+func (e *ConstraintError) Error() string {
+	if e.Constraint == TAG_EXCLUSIVE {
+		return fmt.Sprintf("The options in the set[%v] are mutually exclusive: %v", e.Set,
+			strings.Join(e.Options, ", "))
+	}
+	return fmt.Sprintf("The options in the set[%v] must be given together, but missing: %v",
+		e.Set, strings.Join(e.Options, ", "))
+}
+
+// Errors is the set of the errors collected in one pass of parsing,
+// which is returned if collecting the errors. See Parser.SetCollectErrors.
+type Errors []error
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap makes errors.Is and errors.As check each of the errors.
+func (e Errors) Unwrap() []error {
+	return e
+}
+
+// Set whether to collect all the errors in one pass of parsing, rather than
+// stopping at the first. If true, Parse returns or panics with Errors.
+// The default is false.
+//
+// Notice: the parsing of the command line still stops at the first error.
+func (p *Parser) SetCollectErrors(collect bool) *Parser {
+	p.collect = collect
+	return p
+}
+
+// Record the error if collecting the errors, or panic with it.
+func (p *Parser) fail(err error) {
+	if !p.collect {
+		panic(err)
+	}
+
+	root := p
+	for root.parent != nil {
+		root = root.parent
+	}
+	root.errs = append(root.errs, err)
+}
+
+// Panic with the collected errors if there are.
+func (p *Parser) checkErrors() {
+	root := p
+	for root.parent != nil {
+		root = root.parent
+	}
+
+	if len(root.errs) > 0 {
+		panic(root.errs)
+	}
+}
+
+// Abort parsing with the error and the collected errors.
+func (p *Parser) abort(err error) {
+	p.fail(err)
+	p.checkErrors()
+}
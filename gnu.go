@@ -0,0 +1,163 @@
+package argparse
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// Parse the arguments in the GNU style, then pass the rest arguments,
+// that's, the positional arguments, to the flag set.
+//
+// Like the flag set, it will stop parsing just before the first non-option
+// argument, or after the terminator, "--".
+func (p *Parser) parseGNU(args []string) {
+	for len(args) > 0 {
+		arg := args[0]
+		if len(arg) < 2 || arg[0] != '-' {
+			break
+		}
+
+		args = args[1:]
+		if arg == "--" {
+			break
+		}
+
+		if strings.HasPrefix(arg, "--") {
+			args = p.parseLong(arg[2:], args)
+		} else {
+			args = p.parseShort(arg[1:], args)
+		}
+	}
+
+	p.flagSet.Parse(append([]string{"--"}, args...))
+}
+
+// Parse the long option, such as "--name", "--name=value" or "--name value".
+func (p *Parser) parseLong(arg string, args []string) []string {
+	name, value, has_value := arg, "", false
+	if index := strings.Index(arg, "="); index > 0 {
+		name, value, has_value = arg[:index], arg[index+1:], true
+	}
+
+	// The negated form of the bool option is "--no-NAME".
+	if strings.HasPrefix(name, "no-") && p.isNegated(name[3:]) {
+		name = negPrefix + name[3:]
+	}
+
+	f := p.lookupGNU("--", name)
+	if isBoolFlag(f) {
+		if !has_value {
+			value = "true"
+		}
+	} else if !has_value {
+		if len(args) == 0 {
+			p.failf("flag needs an argument: --%s", name)
+		}
+		value, args = args[0], args[1:]
+	}
+
+	p.setGNU("--", name, value)
+	return args
+}
+
+// Parse the short options, such as "-v", "-xvf", "-p8080" or "-p 8080".
+func (p *Parser) parseShort(arg string, args []string) []string {
+	for i := 0; i < len(arg); i++ {
+		name := arg[i : i+1]
+		if f := p.lookupGNU("-", name); isBoolFlag(f) {
+			p.setGNU("-", name, "true")
+			continue
+		}
+
+		// The rest of the argument is the value, or the next argument is.
+		value := arg[i+1:]
+		if value == "" {
+			if len(args) == 0 {
+				p.failf("flag needs an argument: -%s", name)
+			}
+			value, args = args[0], args[1:]
+		}
+
+		p.setGNU("-", name, value)
+		break
+	}
+	return args
+}
+
+func (p *Parser) lookupGNU(prefix, name string) *flag.Flag {
+	f := p.flagSet.Lookup(name)
+	if f == nil {
+		if name == "help" || name == "h" {
+			p.usage()
+			p.abort(&ParseError{Err: flag.ErrHelp})
+		}
+		p.failUnknown(prefix, name)
+	}
+	return f
+}
+
+func (p *Parser) setGNU(prefix, name, value string) {
+	p.failed = nil
+	if err := p.flagSet.Set(name, value); err != nil {
+		if p.isSecret(name) {
+			value = secretMask
+		}
+		fmt.Fprintf(p.flagSet.Output(), "invalid value %q for flag %s%s: %v\n", value, prefix,
+			name, err)
+		p.usage()
+
+		// The option recorded when being set is the long name even if given by the short.
+		if p.failed == nil {
+			p.failed = &ParseError{Option: name, Value: value, Err: err}
+		}
+		p.abort(p.failed)
+	}
+}
+
+// Return the option name with the prefix, that's, "--" for the long option
+// in the GNU style, or "-".
+func (p *Parser) flagName(name string) string {
+	if p.gnu && len(name) > 1 {
+		return "--" + name
+	}
+	return "-" + name
+}
+
+// Return the negated form of the bool option, that's, "--no-NAME" in the GNU style,
+// or "-no_NAME". Return "" if the option has no negated form.
+func (p *Parser) negatedName(name string) string {
+	if !p.isNegated(name) {
+		return ""
+	} else if p.gnu {
+		return "--no-" + name
+	}
+	return "-" + negPrefix + name
+}
+
+// Return true if the option has the negated form.
+func (p *Parser) isNegated(name string) bool {
+	if f := p.flagSet.Lookup(negPrefix + name); f != nil {
+		_, ok := f.Value.(negatedValue)
+		return ok
+	}
+	return false
+}
+
+// Output the error and the usage, then abort, just like the flag set.
+func (p *Parser) failf(format string, a ...interface{}) {
+	err := fmt.Errorf(format, a...)
+	fmt.Fprintln(p.flagSet.Output(), err)
+	p.usage()
+	p.abort(&ParseError{Err: err})
+}
+
+// Return true if the option is a bool flag, which doesn't need the value.
+func isBoolFlag(f *flag.Flag) bool {
+	if v, ok := f.Value.(interface {
+		IsBoolFlag() bool
+	}); ok {
+		return v.IsBoolFlag()
+	}
+	return false
+}
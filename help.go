@@ -0,0 +1,286 @@
+package argparse
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// The indent of the help of the option.
+const helpIndent = 8
+
+// Set the description of the group, which is shown in the heading of
+// the group in the help. group is the name of the group, which is the name
+// of the registered struct, or the name of the sub-group, such as "server_db".
+func (p *Parser) SetGroupDescription(group, desc string) *Parser {
+	p.gdescs[group] = desc
+	return p
+}
+
+// Set the header of the help, which is shown before the usage line,
+// such as the description and the version of the program.
+func (p *Parser) SetHelpHeader(header string) *Parser {
+	p.header = header
+	return p
+}
+
+// Set the footer of the help, which is shown at last, such as the examples.
+func (p *Parser) SetHelpFooter(footer string) *Parser {
+	p.footer = footer
+	return p
+}
+
+// Set the width of the terminal, by which the help is wrapped.
+//
+// If it's not set or is not positive, it's the environment variable, COLUMNS,
+// or 80 if COLUMNS is not set.
+func (p *Parser) SetHelpWidth(width int) *Parser {
+	p.width = width
+	return p
+}
+
+// Write the help into w, which is also printed when the option, -h or -help,
+// is given or failing to parse the command line.
+//
+// The help consists of the header, the usage line, the positional arguments,
+// the options grouped by the registered structs, the subcommands and the footer.
+// Each option shows its help, type, default value, the constraints of its
+// validators, such as the range and the allowed values, and the environment
+// variable.
+func (p *Parser) WriteHelp(w io.Writer) error {
+	buf := bytes.NewBuffer(nil)
+	width := p.helpWidth()
+
+	if p.header != "" {
+		fmt.Fprintf(buf, "%s\n\n", strings.TrimRight(p.header, "\n"))
+	}
+
+	fmt.Fprintf(buf, "Usage: %s %s\n", filepath.Base(p.flagSet.Name()), p.synopsis())
+
+	if len(p.cmdnames) == 0 && len(p.positionals()) > 0 {
+		fmt.Fprintf(buf, "\nArguments:\n")
+		for _, opt := range p.positionals() {
+			name := opt.metavar
+			if opt.index < 0 {
+				name += "..."
+			}
+			fmt.Fprintf(buf, "  %s\n", name)
+			wrapText(buf, p.describe(opt), helpIndent, width)
+		}
+	}
+
+	p.writeOptions(buf)
+
+	if len(p.cmdnames) > 0 {
+		fmt.Fprintf(buf, "\nCommands:\n")
+		for _, name := range p.cmdnames {
+			fmt.Fprintf(buf, "  %s\n", name)
+			wrapText(buf, p.commands[name].help, helpIndent, width)
+		}
+	}
+
+	if p.footer != "" {
+		fmt.Fprintf(buf, "\n%s\n", strings.TrimRight(p.footer, "\n"))
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Output the help into the output of the flag set.
+func (p *Parser) usage() {
+	p.WriteHelp(p.flagSet.Output())
+}
+
+// Write the options grouped by the groups in the order of registering.
+func (p *Parser) writeOptions(w io.Writer) {
+	width := p.helpWidth()
+	gnames, groups := p.optionGroups()
+	for _, gname := range gnames {
+		if desc := p.gdescs[gname]; desc != "" {
+			fmt.Fprintf(w, "\n%s: %s\n", gname, desc)
+		} else {
+			fmt.Fprintf(w, "\n%s:\n", gname)
+		}
+
+		for _, opt := range groups[gname] {
+			line := "  " + p.optionNames(opt)
+			if typ := p.optionType(opt); typ != "" {
+				line += " " + typ
+			}
+			fmt.Fprintf(w, "%s\n", line)
+			wrapText(w, p.describe(opt), helpIndent, width)
+		}
+	}
+}
+
+// Return the names of the groups in the order of registering,
+// and the options, not including the positional arguments, of each group.
+func (p *Parser) optionGroups() ([]string, map[string][]*option) {
+	var gnames []string
+	groups := make(map[string][]*option)
+	for _, opt := range p.options {
+		if opt.pos != "" {
+			continue
+		}
+		if _, ok := groups[opt.group]; !ok {
+			gnames = append(gnames, opt.group)
+		}
+		groups[opt.group] = append(groups[opt.group], opt)
+	}
+	return gnames, groups
+}
+
+// Return the names of the option, such as "-p, -server_port". The negated form
+// of the bool option is included only if the option is true by default.
+func (p *Parser) optionNames(opt *option) string {
+	names := p.flagName(opt.name)
+	if opt.short != "" {
+		names = "-" + opt.short + ", " + names
+	}
+	if neg := p.negatedName(opt.name); neg != "" && p.flagSet.Lookup(opt.name).DefValue == "true" {
+		names += ", " + neg
+	}
+	return names
+}
+
+// Return the name of the type of the option, which is empty for the bool option.
+func (p *Parser) optionType(opt *option) string {
+	if isBoolFlag(p.flagSet.Lookup(opt.name)) {
+		return ""
+	}
+	return typeName(opt.typ)
+}
+
+// Return the default value of the option, which is empty if not given by the tag.
+func (p *Parser) optionDefault(opt *option) string {
+	if opt.tag.Get(TAG_DEFAULT) == "" {
+		return ""
+	} else if opt.pos != "" {
+		return opt.tag.Get(TAG_DEFAULT)
+	}
+	return p.flagSet.Lookup(opt.name).DefValue
+}
+
+// Return the description of the option, which is the help and the details,
+// such as "the port (default: 80; range: [1, 65535]; env: PORT)".
+func (p *Parser) describe(opt *option) string {
+	var details []string
+	if _default := p.optionDefault(opt); _default != "" {
+		details = append(details, "default: "+fmt.Sprint(maskValue(opt.tag, _default)))
+	}
+	details = append(details, constraints(opt.tag)...)
+	if env := p.getEnvName(opt); env != "" && opt.pos == "" {
+		details = append(details, "env: "+env)
+	}
+	if p.hasFile(opt.name) {
+		details = append(details, "file: "+p.flagName(opt.name+fileSuffix))
+	}
+	if hasStrategy(opt.tag, STRATEGY_REQUIRED) {
+		details = append(details, "required")
+	}
+
+	if len(details) == 0 {
+		return opt.help
+	} else if opt.help == "" {
+		return "(" + strings.Join(details, "; ") + ")"
+	}
+	return opt.help + " (" + strings.Join(details, "; ") + ")"
+}
+
+// Return the constraints of the validators given by the tag, such as
+// "range: [1, 65535]", "values: a|b|c" and "pattern: ^[a-z]+$".
+func constraints(tag reflect.StructTag) []string {
+	var cs []string
+	for _, s := range splitArgs(tag.Get(TAG_VALIDATE)) {
+		if s == "" {
+			continue
+		}
+
+		name, args := parseValidator(s)
+		name = strings.TrimPrefix(name, "validate_")
+		params := validatorParams["validate_"+name]
+
+		// The inline arguments override the keys in the tag.
+		values := make([]string, len(params))
+		for i, param := range params {
+			values[i] = tag.Get(param)
+		}
+		if args != nil && len(params) > 0 {
+			copy(values, paramArgs(params, *args))
+		}
+
+		switch name {
+		case "num_range":
+			cs = append(cs, fmt.Sprintf("range: [%s, %s]", values[0], values[1]))
+		case "str_len":
+			cs = append(cs, fmt.Sprintf("length: [%s, %s]", values[0], values[1]))
+		case "str_array":
+			cs = append(cs, "values: "+strings.Replace(values[0], ",", "|", -1))
+		case "str_regexp":
+			cs = append(cs, "pattern: "+values[0])
+		default:
+			cs = append(cs, "validate: "+s)
+		}
+	}
+	return cs
+}
+
+// Return the name of the type shown in the help, such as "int",
+// "duration" and "[]string".
+func typeName(typ reflect.Type) string {
+	switch {
+	case typ == nil:
+		return ""
+	case typ == durationType:
+		return "duration"
+	case typ.Name() != "":
+		return typ.Name()
+	case typ.Kind() == reflect.Ptr:
+		return typeName(typ.Elem())
+	case typ.Kind() == reflect.Slice:
+		return "[]" + typeName(typ.Elem())
+	case typ.Kind() == reflect.Map:
+		return "map[" + typeName(typ.Key()) + "]" + typeName(typ.Elem())
+	}
+	return typ.String()
+}
+
+// Return the width of the terminal.
+func (p *Parser) helpWidth() int {
+	if p.width > 0 {
+		return p.width
+	} else if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		return width
+	}
+	return 80
+}
+
+// Write the text wrapped by the width, each line of which is indented.
+// The newline in the text is kept.
+func wrapText(w io.Writer, text string, indent, width int) {
+	if text == "" {
+		return
+	}
+
+	prefix := strings.Repeat(" ", indent)
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := prefix
+		for _, word := range strings.Fields(paragraph) {
+			if len(line) > indent && len(line)+1+len(word) > width {
+				fmt.Fprintf(w, "%s\n", line)
+				line = prefix
+			}
+			if len(line) > indent {
+				line += " "
+			}
+			line += word
+		}
+		fmt.Fprintf(w, "%s\n", line)
+	}
+}
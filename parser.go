@@ -13,6 +13,7 @@ import (
 	"os"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/xgfone/go-tools/parse"
 )
@@ -27,6 +28,39 @@ const (
 	// The help content of the option
 	TAG_HELP = "help"
 
+	// The short name of the option, which must be a single letter, such as
+	// `short:"v"`. It's not prefixed by the group name, so it must not be used
+	// by the other options. In the GNU style, the short options can be combined,
+	// such as "-xvf".
+	TAG_SHORT = "short"
+
+	// The name of the environment variable, from which the value of the option
+	// is read, such as `env:"DB_HOST"`. It's not prefixed by the env prefix.
+	TAG_ENV = "env"
+
+	// The position of the positional argument, which is bound to the field,
+	// such as `pos:"0"` and `pos:"1"`, or `pos:"rest"` for the rest positional
+	// arguments, the field of which must be a slice.
+	TAG_POS = "pos"
+
+	// The hint of the shell completion of the value of the option, which is
+	// COMPLETE_FILE or COMPLETE_DIR, such as `complete:"file"`.
+	TAG_COMPLETE = "complete"
+
+	// The separator of the value of the slice or map option, which is used
+	// to split both the default value and each argument, such as `sep:","`.
+	// If it's empty, the value is regarded as only one element. But for map,
+	// the default value is always separated by the comma if it's empty.
+	TAG_SEP = "sep"
+
+	// The names of the mutually exclusive sets separated by the comma, such as
+	// `exclusive:"output"`. At most one option in the same set can be given.
+	TAG_EXCLUSIVE = "exclusive"
+
+	// The names of the co-required sets separated by the comma, such as
+	// `together:"tls"`. If any option in the same set is given, all must be.
+	TAG_TOGETHER = "together"
+
 	// The strategy sets, which a string separated by the comma,
 	// such as "skip,valid".
 	TAG_STRATEGY = "strategy"
@@ -49,28 +83,95 @@ var (
 	// Error
 	NotPointerError = errors.New("Not a pointer to a struct")
 	ExistError      = errors.New("This group has been registered")
+
+	durationType = reflect.TypeOf(time.Duration(0))
 )
 
 type Parser struct {
 	// Panic if true, Or return an error, when failing to parse the options.
 	// The default is true. Deprecated! Please use SetPanic().
 	Panic         bool
+	gnu           bool
+	collect       bool
+	errs          Errors
+	auto_env      bool
+	env_prefix    string
 	default_group string
+	converters    tConverter
+	validators    tValidation
 	cache         map[string]interface{}
+	gnames        []string
+	gvalidators   map[string][]func(interface{}) error
 	group         map[string]interface{}
+	options       []*option
+	posset        map[string]bool
 	flagSet       *flag.FlagSet
+
+	// For the sources of the values
+	sources map[string]*Source
+	from    string
+	where   string
+
+	// The option failing to be set from the command line, and the value
+	// of the secret option failing to be set, which is masked in the error
+	// of the flag set.
+	failed       *ParseError
+	failedSecret string
+
+	// For the help
+	gdescs map[string]string
+	header string
+	footer string
+	width  int
+
+	// For the subcommands
+	parent   *Parser
+	help     string
+	command  string
+	commands map[string]*Parser
+	cmdnames []string
+}
+
+// option is the information of a registered option, in the order of registering.
+type option struct {
+	name  string // The name of the option, which is prefixed by the group name
+	short string // The short name of the option
+	env   string // The name of the environment variable given by the tag
+	group string // The name of the group that the option belongs to
+	field string // The name of the field
+	help  string // The help of the option
+	typ   reflect.Type
+	tag   reflect.StructTag
+
+	// For the positional argument
+	pos     string // The position, such as "0" or "rest"
+	index   int    // The index of the position, which is -1 for "rest"
+	metavar string // The name shown in the usage, such as "SOURCE"
 }
 
 // New create a new parser.
 func NewParser() *Parser {
 	Debugf("The default group name is Default")
-	return &Parser{
+	return newParser(os.Args[0])
+}
+
+func newParser(name string) *Parser {
+	p := &Parser{
 		Panic:         true,
 		default_group: "Default",
+		converters:    make(tConverter),
+		validators:    make(tValidation),
 		cache:         make(map[string]interface{}),
+		gvalidators:   make(map[string][]func(interface{}) error),
+		posset:        make(map[string]bool),
+		gdescs:        make(map[string]string),
+		sources:       make(map[string]*Source),
 		group:         make(map[string]interface{}),
-		flagSet:       flag.NewFlagSet(os.Args[0], flag.PanicOnError),
+		flagSet:       flag.NewFlagSet(name, flag.ContinueOnError),
+		commands:      make(map[string]*Parser),
 	}
+	p.flagSet.Usage = p.usage
+	return p
 }
 
 // Set the name of the default group. Must set it before registering the options.
@@ -85,18 +186,44 @@ func (p *Parser) SetPanic(is_panic bool) *Parser {
 	return p
 }
 
+// Set whether the parser parses the arguments in the GNU style.
+//
+// In the GNU style, the long options must be prefixed by "--", such as
+// "--verbose" and "--port=80", and the short options are prefixed by "-",
+// which can be combined, such as "-xvf", or attached with the value,
+// such as "-p8080". The default is false, that's, the style of the flag package.
+func (p *Parser) SetGNU(gnu bool) *Parser {
+	p.gnu = gnu
+	return p
+}
+
 // Parse the arguments to the registered structs.
 //
 // If args is not nil, it's the arguments. Or use os.Args[1:].
+// The value of the option is from the command line, the environment variable,
+// the configuration file loaded by LoadFile, and the default value given by
+// the tag, in order.
 // If it has been parsed, don't parse it again.
 // For parsing it againt, you can create a new parser.
 //
-// Notice: It will panic if Panic is true when failing to parse.
+// If there are the subcommands added by AddCommand, the first positional
+// argument is the name of the subcommand, and the rest are parsed by it.
+//
+// Return *ParseError if failing to parse the arguments, *RequiredError if missing
+// the required options, *ConstraintError if violating the exclusive or together
+// sets, *ValidationError if failing to validate the option, or Errors including
+// all of them if collecting the errors.
+//
+// Notice: It will panic with the error if Panic is true when failing to parse.
 func (p *Parser) Parse(args []string) (err error) {
 	defer func() {
 		if !p.Panic {
 			if _err := recover(); _err != nil {
-				err = fmt.Errorf("%v", _err)
+				if e, ok := _err.(error); ok {
+					err = e
+				} else {
+					err = fmt.Errorf("%v", _err)
+				}
 			}
 		}
 	}()
@@ -109,11 +236,31 @@ func (p *Parser) Parse(args []string) (err error) {
 		args = os.Args[1:]
 	}
 
-	p.flagSet.Parse(args)
-	p.setValues()
+	p.parse(args)
 	return nil
 }
 
+func (p *Parser) parse(args []string) {
+	if p.Parsed() {
+		return
+	}
+
+	p.parseEnv()
+	p.setSource(SOURCE_COMMAND, "")
+	if p.gnu {
+		p.parseGNU(args)
+	} else {
+		p.parseFlags(args)
+	}
+	p.parsePositional()
+	p.checkRequired()
+	p.checkConstraints()
+	p.setValues()
+	p.validateGroups()
+	p.parseCommand()
+	p.checkErrors()
+}
+
 // Return true if parsed, or false.
 func (p Parser) Parsed() bool {
 	return p.flagSet.Parsed()
@@ -125,7 +272,10 @@ func (p Parser) Parsed() bool {
 // Return nil if successfully.
 //
 // When registering a struct, only the exposed field. If the type of the field
-// is not supported, skip it.
+// is not supported, skip it. The nested struct field is registered as
+// a sub-group, whose options are prefixed by the name of the group and
+// the field, such as "server_db_host", and the anonymous embedded struct is
+// flattened into the parent group.
 //
 // When parsing the arguments, it will parse the result to the field of the struct.
 //
@@ -146,14 +296,123 @@ func (p *Parser) Register(group interface{}) error {
 	}
 
 	// Register options.
+	start := len(p.options)
 	p.register_flag(name, vg)
+	p.register_negated(p.options[start:])
+	p.register_files(p.options[start:])
 	p.cache[name] = group
+	p.gnames = append(p.gnames, name)
 	return nil
 }
 
 func (p *Parser) setValues() {
-	for k, v := range p.cache {
-		p.setGroup(k, reflect.ValueOf(v).Elem())
+	for _, name := range p.gnames {
+		p.setGroup(name, reflect.ValueOf(p.cache[name]).Elem())
+	}
+}
+
+// Return the names of the options which have been set by any source.
+func (p *Parser) visited() map[string]bool {
+	set := make(map[string]bool)
+	p.flagSet.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	// The short name is the alias of the option.
+	for _, opt := range p.options {
+		if opt.short != "" && set[opt.short] {
+			set[opt.name] = true
+		}
+		if set[negPrefix+opt.name] && p.isNegated(opt.name) {
+			set[opt.name] = true
+		}
+		if set[opt.name+fileSuffix] && p.hasFile(opt.name) {
+			set[opt.name] = true
+		}
+	}
+
+	for name := range p.posset {
+		set[name] = true
+	}
+	return set
+}
+
+// Return the bool options which are given only by the negated form,
+// such as -no_verbose, in the command line.
+func (p *Parser) negatedOnly() map[string]bool {
+	set := make(map[string]bool)
+	p.flagSet.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	negated := make(map[string]bool)
+	for _, opt := range p.options {
+		if set[negPrefix+opt.name] && p.isNegated(opt.name) && !set[opt.name] &&
+			!(opt.short != "" && set[opt.short]) {
+			negated[opt.name] = true
+		}
+	}
+	return negated
+}
+
+// Check whether all the required options have been set. If not, fail.
+func (p *Parser) checkRequired() {
+	var missing []string
+	set := p.visited()
+	for _, opt := range p.options {
+		// If there are the subcommands, the positional arguments are not bound.
+		if opt.pos != "" && len(p.commands) > 0 {
+			continue
+		}
+
+		if hasStrategy(opt.tag, STRATEGY_REQUIRED) && !set[opt.name] {
+			if opt.pos != "" {
+				missing = append(missing, opt.metavar)
+			} else {
+				missing = append(missing, p.flagName(opt.name))
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		p.fail(&RequiredError{Options: missing})
+	}
+}
+
+// Check whether the options violate the exclusive or together sets. If so, fail.
+func (p *Parser) checkConstraints() {
+	set, negated := p.visited(), p.negatedOnly()
+	for _, constraint := range []string{TAG_EXCLUSIVE, TAG_TOGETHER} {
+		var names []string
+		given := make(map[string][]string)
+		missing := make(map[string][]string)
+		for _, opt := range p.options {
+			// Only giving the negated form, such as -no_yaml, doesn't choose
+			// the option from the exclusive set.
+			chosen := set[opt.name] && !(constraint == TAG_EXCLUSIVE && negated[opt.name])
+			for _, name := range strings.Split(opt.tag.Get(constraint), ",") {
+				if name = strings.TrimSpace(name); name == "" {
+					continue
+				}
+
+				if !contains(names, name) {
+					names = append(names, name)
+				}
+				if chosen {
+					given[name] = append(given[name], p.flagName(opt.name))
+				} else {
+					missing[name] = append(missing[name], p.flagName(opt.name))
+				}
+			}
+		}
+
+		for _, name := range names {
+			if constraint == TAG_EXCLUSIVE && len(given[name]) > 1 {
+				p.fail(&ConstraintError{Constraint: constraint, Set: name, Options: given[name]})
+			} else if constraint == TAG_TOGETHER && len(given[name]) > 0 && len(missing[name]) > 0 {
+				p.fail(&ConstraintError{Constraint: constraint, Set: name, Options: missing[name]})
+			}
+		}
 	}
 }
 
@@ -167,12 +426,25 @@ func (p Parser) getName(gname, fname string) string {
 	return strings.ToLower(name)
 }
 
+// Return true if the type is a struct which is regarded as a sub-group,
+// that's, it is neither converted by the converters nor implements flag.Value
+// or encoding.TextUnmarshaler.
+func (p *Parser) isGroup(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Struct && p.getConverter(typ) == nil &&
+		!isCustomType(reflect.PtrTo(typ))
+}
+
 func (p *Parser) setGroup(gname string, group reflect.Value) {
 	tg := group.Type()
 	num := group.NumField()
 	for i := 0; i < num; i++ {
 		field := tg.Field(i)
 
+		// The unexported field can't be set, but the anonymous struct is flattened.
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
 		// If the strategies are not passed, skip it.
 		if !validStrategy(field.Tag) {
 			continue
@@ -180,21 +452,52 @@ func (p *Parser) setGroup(gname string, group reflect.Value) {
 
 		fname := getFromTag(field.Tag, TAG_NAME, field.Name)
 		name := p.getName(gname, fname)
+
+		if p.isGroup(field.Type) {
+			if field.Anonymous {
+				p.setGroup(gname, group.Field(i))
+			} else {
+				p.setGroup(name, group.Field(i))
+			}
+			continue
+		}
+
 		v, ok := p.group[name]
 		if !ok {
 			Infof("Can't lookup the option: %v", name)
 			continue
 		}
 
-		if err := validators.Validate(field.Tag, reflect.ValueOf(v).Elem().Interface()); err != nil {
-			msg := fmt.Sprintf("Failed to validate the field[%v.%v]: %v", gname, field.Name, err)
-			panic(msg)
+		value := getValue(v)
+		if err := p.validate(field.Tag, value); err != nil {
+			err.Group, err.Field, err.Option = gname, field.Name, name
+			if hasStrategy(field.Tag, STRATEGY_SECRET) {
+				err.Value, err.Err = secretMask, maskError(err.Err)
+			}
+			p.fail(err)
+			continue
 		}
 
-		Debugf("Parsing [%v]:[%v] to %v.%v", name, reflect.ValueOf(v).Elem().Interface(),
-			gname, field.Name)
+		Debugf("Parsing [%v]:[%v] to %v.%v", name, maskValue(field.Tag, value), gname, field.Name)
 
 		vfield := group.Field(i)
+
+		// The value implementing flag.Getter has the same type as the field.
+		if _, ok := v.(flag.Getter); ok {
+			if value == nil {
+				vfield.Set(reflect.Zero(vfield.Type()))
+			} else {
+				vfield.Set(reflect.ValueOf(value))
+			}
+			continue
+		}
+
+		// time.Duration is an int64, so check it before the kind.
+		if vfield.Type() == durationType {
+			vfield.SetInt(int64(*v.(*time.Duration)))
+			continue
+		}
+
 		switch vfield.Kind() {
 		case reflect.String:
 			vfield.SetString(*v.(*string))
@@ -220,6 +523,11 @@ func (p *Parser) register_flag(gname string, group reflect.Value) {
 		// Calculate the name, the default value and help by the tag of the field.
 		field := group.Type().Field(i)
 
+		// The unexported field can't be set, but the anonymous struct is flattened.
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
 		// If the strategies are not passed, skip it.
 		if !validStrategy(field.Tag) {
 			continue
@@ -230,39 +538,214 @@ func (p *Parser) register_flag(gname string, group reflect.Value) {
 		fname := getFromTag(field.Tag, TAG_NAME, field.Name)
 		name := p.getName(gname, fname)
 
-		Debugf("Registering the option: name[%v] default[%v] help[%v]", name, _default, usage)
+		// The nested struct is registered as a sub-group, whose name is
+		// the name of the option. But the anonymous struct is flattened
+		// into the current group.
+		if p.isGroup(field.Type) {
+			if field.Anonymous {
+				Debugf("Flattening the anonymous struct %v into the group %v", field.Name, gname)
+				p.register_flag(gname, group.Field(i))
+			} else {
+				Debugf("Registering the sub-group: %v", name)
+				p.register_flag(name, group.Field(i))
+			}
+			continue
+		}
 
-		switch group.Field(i).Kind() {
-		case reflect.Bool:
-			// For bool, the default is always false, and can't be true.
-			// If true, the option is always true.
-			// value := parse.ToBool(_default)
-			p.group[name] = p.flagSet.Bool(name, false, usage)
-		case reflect.String:
-			p.group[name] = p.flagSet.String(name, _default, usage)
-		case reflect.Float32:
-			value := parse.ToF64(_default)
-			p.group[name] = p.flagSet.Float64(name, value, usage)
-		case reflect.Float64:
-			value := parse.ToF64(_default)
-			p.group[name] = p.flagSet.Float64(name, value, usage)
-		case reflect.Int64:
-			value := parse.ToI64(_default, 10)
-			p.group[name] = p.flagSet.Int64(name, value, usage)
-		case reflect.Uint64:
-			value := parse.ToU64(_default, 10)
-			p.group[name] = p.flagSet.Uint64(name, value, usage)
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
-			value := parse.ToInt(_default, 10)
-			p.group[name] = p.flagSet.Int(name, value, usage)
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
-			value := parse.ToUint(_default, 10)
-			p.group[name] = p.flagSet.Uint(name, value, usage)
-		default:
+		// The positional argument is not registered into the flag set.
+		if pos := getFromTag(field.Tag, TAG_POS, ""); pos != "" {
+			p.register_positional(name, fname, pos, _default, usage, gname, field)
+			continue
+		}
+
+		Debugf("Registering the option: name[%v] default[%v] help[%v]", name,
+			maskValue(field.Tag, _default), usage)
+
+		if !p.register_option(name, _default, usage, field) {
 			Debugf("Don't support the type, %v, so skip to register the option: %v.%v",
-				group.Field(i).Type().String(), gname, field.Name)
+				field.Type.String(), gname, field.Name)
+			continue
+		}
+
+		// Record the source of the value when it's set.
+		f := p.flagSet.Lookup(name)
+		f.Value = sourceValue{Value: f.Value, name: name, parser: p}
+
+		// The short name is the alias of the option.
+		if short := getFromTag(field.Tag, TAG_SHORT, ""); short != "" {
+			if len(short) != 1 {
+				panic(fmt.Sprintf("The short name of the option[%v] is not a single letter: %v",
+					name, short))
+			} else if p.flagSet.Lookup(short) != nil {
+				panic(fmt.Sprintf("The short name of the option[%v] has been registered: %v",
+					name, short))
+			}
+			p.flagSet.Var(p.flagSet.Lookup(name).Value, short, usage)
 		}
+
+		p.options = append(p.options, &option{
+			name:  name,
+			short: getFromTag(field.Tag, TAG_SHORT, ""),
+			env:   getFromTag(field.Tag, TAG_ENV, ""),
+			group: gname,
+			field: field.Name,
+			help:  usage,
+			typ:   field.Type,
+			tag:   field.Tag,
+		})
+	}
+}
+
+// Register the negated forms of the bool options, such as -no_verbose.
+//
+// They are registered after all the options of the struct, so the negated
+// form is skipped if there is the option with the same name, whether the option
+// is defined before or after the bool option.
+func (p *Parser) register_negated(options []*option) {
+	for _, opt := range options {
+		if opt.pos != "" || opt.typ.Kind() != reflect.Bool {
+			continue
+		}
+
+		f := p.flagSet.Lookup(opt.name)
+		if isBoolFlag(f) && p.flagSet.Lookup(negPrefix+opt.name) == nil {
+			p.flagSet.Var(negatedValue{f.Value}, negPrefix+opt.name, opt.help)
+		}
+	}
+}
+
+// Register the companions of the secret options, such as -db_password_file,
+// which read the values from the files.
+//
+// Like the negated forms, they are registered after all the options of
+// the struct, and skipped if there is the option with the same name.
+func (p *Parser) register_files(options []*option) {
+	for _, opt := range options {
+		if opt.pos != "" || !hasStrategy(opt.tag, STRATEGY_SECRET) {
+			continue
+		}
+
+		if name := opt.name + fileSuffix; p.flagSet.Lookup(name) == nil {
+			p.flagSet.Var(&fileValue{value: p.flagSet.Lookup(opt.name).Value}, name,
+				fmt.Sprintf("the file from which the value of %v is read", p.flagName(opt.name)))
+		}
+	}
+}
+
+// Register the option into the flag set by the type of the field.
+//
+// Return true if registering successfully, or false if the type is not supported.
+func (p *Parser) register_option(name, _default, usage string, field reflect.StructField) bool {
+	ftype := field.Type
+
+	// The converters and the custom types are consulted before the built-in types.
+	if p.getConverter(ftype) != nil || isCustomType(reflect.PtrTo(ftype)) {
+		value, _ := p.newValue(name, _default, field)
+		p.flagSet.Var(value, name, usage)
+		p.group[name] = value
+		return true
+	}
+
+	// time.Duration is an int64, so check it before the kind.
+	if ftype == durationType {
+		value, _ := time.ParseDuration(_default)
+		p.group[name] = p.flagSet.Duration(name, value, usage)
+		return true
+	}
+
+	switch ftype.Kind() {
+	case reflect.Bool:
+		// If the default is true, the option is set to false by the negated form.
+		value := parse.ToBool(_default)
+		p.group[name] = p.flagSet.Bool(name, value, usage)
+	case reflect.String:
+		p.group[name] = p.flagSet.String(name, _default, usage)
+	case reflect.Float32:
+		value := parse.ToF64(_default)
+		p.group[name] = p.flagSet.Float64(name, value, usage)
+	case reflect.Float64:
+		value := parse.ToF64(_default)
+		p.group[name] = p.flagSet.Float64(name, value, usage)
+	case reflect.Int64:
+		value := parse.ToI64(_default, 10)
+		p.group[name] = p.flagSet.Int64(name, value, usage)
+	case reflect.Uint64:
+		value := parse.ToU64(_default, 10)
+		p.group[name] = p.flagSet.Uint64(name, value, usage)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		value := parse.ToInt(_default, 10)
+		p.group[name] = p.flagSet.Int(name, value, usage)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		value := parse.ToUint(_default, 10)
+		p.group[name] = p.flagSet.Uint(name, value, usage)
+	default:
+		value, ok := p.newValue(name, _default, field)
+		if !ok {
+			return false
+		}
+		p.flagSet.Var(value, name, usage)
+		p.group[name] = value
+	}
+	return true
+}
+
+// Create a new flag.Value, which also implements flag.Getter, by the type of
+// the field, and set the default value.
+//
+// Return false if the type is not supported.
+func (p *Parser) newValue(name, _default string, field reflect.StructField) (flag.Value, bool) {
+	ftype := field.Type
+	sep := getFromTag(field.Tag, TAG_SEP, "")
+
+	// The converters are consulted before the built-in types.
+	if p.getConverter(ftype) != nil {
+		value, err := newScalarValue(ftype, _default, p.convert)
+		debugDefault(name, _default, field, err)
+		return value, true
+	}
+
+	// The field whose pointer implements flag.Value or encoding.TextUnmarshaler
+	// is registered by itself, such as net.IP, big.Int, etc.
+	if value, ok := newCustomValue(ftype); ok {
+		if _default != "" {
+			debugDefault(name, _default, field, value.Set(_default))
+		}
+		return value, true
+	}
+
+	switch ftype.Kind() {
+	case reflect.Slice:
+		if p.canConvert(ftype.Elem()) {
+			value, err := newSliceValue(ftype, sep, _default, p.convert)
+			debugDefault(name, _default, field, err)
+			return value, true
+		}
+	case reflect.Map:
+		if p.canConvert(ftype.Key()) && p.canConvert(ftype.Elem()) {
+			value, err := newMapValue(ftype, sep, _default, p.convert)
+			debugDefault(name, _default, field, err)
+			return value, true
+		}
+	default:
+		if canConvert(ftype) {
+			value, err := newScalarValue(ftype, _default, p.convert)
+			debugDefault(name, _default, field, err)
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// Log the error failing to set the default value of the option, which are
+// masked if the option is secret.
+func debugDefault(name, _default string, field reflect.StructField, err error) {
+	if err == nil {
+		return
+	} else if hasStrategy(field.Tag, STRATEGY_SECRET) {
+		err = maskError(err)
 	}
+	Debugf("Failed to set the default value[%v] of the option[%v]: %v",
+		maskValue(field.Tag, _default), name, err)
 }
 
 // The proxy of flag.FlagSet.Arg().
@@ -280,6 +763,8 @@ func (p *Parser) NArg() int {
 	return p.flagSet.NArg()
 }
 
+// Print the options grouped by the registered structs into the output of
+// the flag set, which is os.Stderr by default. See WriteHelp.
 func (p *Parser) PrintDefaults() {
-	p.flagSet.PrintDefaults()
+	p.writeOptions(p.flagSet.Output())
 }
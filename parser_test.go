@@ -1,8 +1,17 @@
 package argparse_test
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/xgfone/argparse"
 )
@@ -10,7 +19,7 @@ import (
 func ExampleParser() {
 	type Default struct {
 		String string `name:"str" default:"0.0.0.0", help:"the ip to listen to" validate:"validate_str_not_empty"`
-		Bool   bool   // The default is useless, and it will be ignored.
+		Bool   bool   // It can be turned off by -no_bool if the default is true.
 
 		Float32 float32 `default:"RRRR"` // The default value is ZERO
 		Float64 float64 `default:"1.2"`
@@ -30,7 +39,7 @@ func ExampleParser() {
 
 	type Group struct {
 		String string `name:"str" default:"0.0.0.0" help:"the ip to listen to"`
-		Bool   bool   // The default is useless, and it will be ignored.
+		Bool   bool   // It can be turned off by -no_bool if the default is true.
 
 		Float32 float32 `default:"RRRR"` // The default value is ZERO
 		Float64 float64 `default:"1.2"`
@@ -65,3 +74,776 @@ func ExampleParser() {
 	// argparse_test.Group{String:127.0.0.1 Bool:false Float32:2.5 Float64:1.2 Int:123 Int8:123 Int16:123 Int32:456 Int64:123 Uint:123 Uint8:123 Uint16:123 Uint32:456 Uint64:0}
 	// 2 [Arg1 Arg2]
 }
+
+func ExampleParser_duration() {
+	type Server struct {
+		Timeout time.Duration `default:"30s" help:"the timeout of the request"`
+		Idle    time.Duration `default:"1m30s"`
+	}
+
+	p := argparse.NewParser()
+	server := Server{}
+
+	p.Register(&server)
+	p.Parse([]string{"-server_timeout", "5s"})
+
+	fmt.Printf("%v %v\n", server.Timeout, server.Idle)
+
+	// Output:
+	// 5s 1m30s
+}
+
+func ExampleParser_slice() {
+	type Cluster struct {
+		Peers []string        `name:"peer" default:"127.0.0.1:80"`
+		Tags  []string        `name:"tag" default:"a,b" sep:","`
+		Ports []int           `name:"port" sep:","`
+		Waits []time.Duration `name:"wait" default:"1s"`
+	}
+
+	p := argparse.NewParser()
+	cluster := Cluster{}
+
+	p.Register(&cluster)
+	p.Parse(strings.Split("-cluster_peer 10.0.0.1:80 -cluster_peer 10.0.0.2:80 -cluster_port 80,443 -cluster_port 8080", " "))
+
+	fmt.Printf("%v %v %v %v\n", cluster.Peers, cluster.Tags, cluster.Ports, cluster.Waits)
+
+	// Output:
+	// [10.0.0.1:80 10.0.0.2:80] [a b] [80 443 8080] [1s]
+}
+
+func ExampleParser_map() {
+	type Route struct {
+		Labels  map[string]string `name:"label" default:"env=dev,team=ops"`
+		Weights map[string]int    `name:"weight"`
+	}
+
+	p := argparse.NewParser()
+	route := Route{}
+
+	p.Register(&route)
+	p.Parse(strings.Split("-route_label env=prod -route_label team=infra -route_weight a=1", " "))
+
+	fmt.Printf("%v %v\n", route.Labels, route.Weights)
+
+	// Output:
+	// map[env:prod team:infra] map[a:1]
+}
+
+type level int
+
+func (l *level) String() string { return [...]string{"debug", "info", "error"}[*l] }
+
+func (l *level) Set(s string) error {
+	for i, name := range [...]string{"debug", "info", "error"} {
+		if s == name {
+			*l = level(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown level %q", s)
+}
+
+func ExampleParser_value() {
+	type Log struct {
+		Level level  `default:"info"`
+		IP    net.IP `default:"127.0.0.1"`
+		Mask  net.IP
+	}
+
+	p := argparse.NewParser()
+	log := Log{}
+
+	p.Register(&log)
+	p.Parse(strings.Split("-log_level error -log_mask 255.255.255.0", " "))
+
+	fmt.Printf("%v %v %v\n", log.Level.String(), log.IP, log.Mask)
+
+	// Output:
+	// error 127.0.0.1 255.255.255.0
+}
+
+func ExampleParser_RegisterConverter() {
+	type Proxy struct {
+		Match   *regexp.Regexp `default:"^/api/"`
+		Servers []url.URL      `name:"server" sep:","`
+	}
+
+	p := argparse.NewParser()
+	p.RegisterConverter(reflect.TypeOf((*regexp.Regexp)(nil)), func(s string) (interface{}, error) {
+		return regexp.Compile(s)
+	})
+	p.RegisterConverter(reflect.TypeOf(url.URL{}), func(s string) (interface{}, error) {
+		u, err := url.Parse(s)
+		if err != nil {
+			return nil, err
+		}
+		return *u, nil
+	})
+
+	proxy := Proxy{}
+	p.Register(&proxy)
+	p.Parse(strings.Split("-proxy_server http://a.com,http://b.com", " "))
+
+	fmt.Printf("%v %v %v\n", proxy.Match, proxy.Servers[0].Host, proxy.Servers[1].Host)
+
+	// Output:
+	// ^/api/ a.com b.com
+}
+
+func ExampleParser_nested() {
+	type Base struct {
+		Debug bool
+	}
+
+	type Server struct {
+		Base
+
+		Addr string `default:":80"`
+		DB   struct {
+			Host string `default:"127.0.0.1"`
+			Port int    `default:"3306"`
+		}
+		TLS struct {
+			Cert struct {
+				Path string
+			}
+		} `name:"tls"`
+	}
+
+	p := argparse.NewParser()
+	server := Server{}
+
+	p.Register(&server)
+	p.Parse(strings.Split("-server_debug -server_db_host 10.0.0.1 -server_tls_cert_path /etc/cert.pem", " "))
+
+	fmt.Printf("%v %v %v %v %v\n", server.Debug, server.Addr, server.DB.Host, server.DB.Port, server.TLS.Cert.Path)
+
+	// Output:
+	// true :80 10.0.0.1 3306 /etc/cert.pem
+}
+
+func ExampleParser_unexported() {
+	type DB struct {
+		Host string
+		key  string
+	}
+
+	type Server struct {
+		Addr string
+		DB   DB
+		mu   sync.Mutex
+		Lock sync.Mutex
+	}
+
+	p := argparse.NewParser().SetPanic(false)
+	server := Server{}
+
+	p.Register(&server)
+	err := p.Parse(strings.Split("-server_addr :80 -server_db_host 10.0.0.1", " "))
+
+	fmt.Println(err, server.Addr, server.DB.Host)
+
+	// Output:
+	// <nil> :80 10.0.0.1
+}
+
+func ExampleParser_SetGNU() {
+	type Default struct {
+		Verbose bool   `short:"v"`
+		Extract bool   `short:"x"`
+		Port    int    `short:"p" default:"80"`
+		File    string `short:"f"`
+	}
+
+	type Log struct {
+		Level string `default:"info"`
+	}
+
+	p := argparse.NewParser().SetGNU(true)
+	default_ := Default{}
+	log := Log{}
+
+	p.Register(&default_)
+	p.Register(&log)
+	p.Parse(strings.Split("-xvf a.tar -p8080 --log_level=debug Arg1", " "))
+
+	fmt.Printf("%+v %+v %v\n", default_, log, p.Args())
+
+	// Output:
+	// {Verbose:true Extract:true Port:8080 File:a.tar} {Level:debug} [Arg1]
+}
+
+func ExampleParser_SetAutoEnv() {
+	type DB struct {
+		Host  string   `default:"127.0.0.1" env:"DB_HOST"`
+		Port  int      `default:"3306"`
+		Users []string `name:"user" sep:","`
+	}
+
+	os.Setenv("DB_HOST", "10.0.0.1")
+	os.Setenv("APP_DB_PORT", "3307")
+	os.Setenv("APP_DB_USER", "root,admin")
+	defer os.Unsetenv("DB_HOST")
+	defer os.Unsetenv("APP_DB_PORT")
+	defer os.Unsetenv("APP_DB_USER")
+
+	p := argparse.NewParser().SetAutoEnv(true).SetEnvPrefix("APP")
+	db := DB{}
+
+	p.Register(&db)
+	p.Parse(strings.Split("-db_port 3308 -db_user guest", " "))
+
+	fmt.Printf("%+v\n", db)
+
+	// Output:
+	// {Host:10.0.0.1 Port:3308 Users:[guest]}
+}
+
+func ExampleParser_LoadINI() {
+	type Default struct {
+		String string `name:"str" default:"0.0.0.0"`
+	}
+
+	type Group struct {
+		String string   `name:"str"`
+		Int    int      `default:"123"`
+		Peers  []string `name:"peer"`
+		DB     struct {
+			Host string
+		}
+	}
+
+	p := argparse.NewParser()
+	default_ := Default{}
+	group := Group{}
+
+	p.Register(&default_)
+	p.Register(&group)
+	p.LoadINI(strings.NewReader(`
+str = 127.0.0.1
+
+[group]
+str = "1.2.3.4"
+int = 456
+peer = a
+peer = b
+
+[group.db]
+host = 10.0.0.1
+`))
+	p.Parse(strings.Split("-group_int 789", " "))
+
+	fmt.Printf("%+v %+v\n", default_, group)
+
+	// Output:
+	// {String:127.0.0.1} {String:1.2.3.4 Int:789 Peers:[a b] DB:{Host:10.0.0.1}}
+}
+
+func ExampleParser_LoadJSON() {
+	type Group struct {
+		String string            `name:"str"`
+		Peers  []string          `name:"peer"`
+		Labels map[string]string `name:"label"`
+		DB     struct {
+			Port int
+		}
+	}
+
+	p := argparse.NewParser()
+	group := Group{}
+
+	p.Register(&group)
+	p.LoadJSON(strings.NewReader(`{"group": {"str": "a", "peer": ["b", "c"],
+		"label": {"env": "prod"}, "db": {"port": 3306}}}`))
+	p.Parse(strings.Split("-group_peer d", " "))
+
+	fmt.Printf("%+v\n", group)
+
+	// Output:
+	// {String:a Peers:[d] Labels:map[env:prod] DB:{Port:3306}}
+}
+
+func ExampleParser_required() {
+	type Server struct {
+		Host string `strategy:"required"`
+		Port int    `strategy:"required" default:"80"`
+		Addr string `strategy:"required"`
+	}
+
+	p := argparse.NewParser().SetPanic(false)
+	server := Server{}
+
+	p.Register(&server)
+	err := p.Parse(strings.Split("-server_port 0", " "))
+
+	fmt.Println(err)
+
+	// Output:
+	// Missing the required options: -server_host, -server_addr
+}
+
+func ExampleParser_AddCommand() {
+	type Default struct {
+		Debug bool
+	}
+
+	type Serve struct {
+		Port int `default:"80"`
+	}
+
+	type Migrate struct {
+		DryRun bool `name:"dry_run"`
+	}
+
+	p := argparse.NewParser()
+	default_ := Default{}
+	serve := Serve{}
+	migrate := Migrate{}
+
+	p.Register(&default_)
+	p.AddCommand("serve", "Start the server").Register(&serve)
+	p.AddCommand("migrate", "Migrate the database").Register(&migrate)
+	p.Parse(strings.Split("-debug serve -serve_port 8080 Arg1", " "))
+
+	fmt.Printf("%+v %+v %+v\n", default_, serve, migrate)
+	fmt.Printf("%v %v\n", p.Command(), p.AddCommand("serve", "").Args())
+
+	// Output:
+	// {Debug:true} {Port:8080} {DryRun:false}
+	// serve [Arg1]
+}
+
+func ExampleParser_SetCollectErrors() {
+	type Server struct {
+		Host string `strategy:"required"`
+		Port int    `validate:"validate_num_range" min:"1" max:"65535"`
+		Addr string `default:"" validate:"validate_str_not_empty"`
+	}
+
+	p := argparse.NewParser().SetPanic(false).SetCollectErrors(true)
+	server := Server{}
+
+	p.Register(&server)
+	err := p.Parse(strings.Split("-server_port 70000", " "))
+
+	fmt.Println(err)
+
+	var verr *argparse.ValidationError
+	if errors.As(err, &verr) {
+		fmt.Println(verr.Option, verr.Validator, verr.Value)
+	}
+
+	// Output:
+	// Missing the required options: -server_host
+	// Failed to validate the field[Server.Port]: [validate_num_range] the value 70000 is more than 65535
+	// Failed to validate the field[Server.Addr]: [validate_str_not_empty] The string is empty
+	// server_port validate_num_range 70000
+}
+
+func ExampleParseError() {
+	type Server struct {
+		Port int `short:"p"`
+	}
+
+	for _, p := range []*argparse.Parser{argparse.NewParser(), argparse.NewParser().SetGNU(true)} {
+		p.SetPanic(false).Register(&Server{})
+		err := p.Parse(strings.Split("-p abc", " "))
+
+		var perr *argparse.ParseError
+		if errors.As(err, &perr) {
+			fmt.Println(perr.Option, perr.Value, perr.Err)
+		}
+	}
+
+	// Output:
+	// server_port abc parse error
+	// server_port abc parse error
+}
+
+func ExampleParser_RegisterValidator() {
+	type Server struct {
+		Port int `default:"80" validate:"validate_port"`
+	}
+
+	p := argparse.NewParser().SetPanic(false)
+	p.RegisterValidator("validate_port", func(tag string, value interface{}) error {
+		if port := value.(int); port < 1024 {
+			return fmt.Errorf("the port %v is privileged", port)
+		}
+		return nil
+	})
+
+	server := Server{}
+	p.Register(&server)
+	fmt.Println(p.Parse(strings.Split("-server_port 443", " ")))
+	fmt.Println(p.GetValidator("validate_port") != nil, argparse.GetValidator("validate_port") == nil)
+
+	// Output:
+	// Failed to validate the field[Server.Port]: [validate_port] the port 443 is privileged
+	// true true
+}
+
+func ExampleParser_inlineValidator() {
+	type Server struct {
+		Port  int    `default:"80" validate:"num_range(1,65535)"`
+		Name  string `default:"abc1" validate:"str_len(1,8),str_regexp(^[a-z]+),str_regexp([0-9]$)"`
+		Level string `default:"info" validate:"str_array(debug,info,error)"`
+	}
+
+	p := argparse.NewParser().SetPanic(false).SetCollectErrors(true)
+	server := Server{}
+
+	p.Register(&server)
+	fmt.Println(p.Parse(strings.Split("-server_port 0 -server_name abc -server_level warn", " ")))
+
+	// Output:
+	// Failed to validate the field[Server.Port]: [num_range(1,65535)] the value 0 is less than 1
+	// Failed to validate the field[Server.Name]: [str_regexp([0-9]$)] The value doesn't match the pattern
+	// Failed to validate the field[Server.Level]: [str_array(debug,info,error)] The value[warn] is not in [debug info error]
+}
+
+type Pool struct {
+	MinConns int `name:"min_conns" default:"1"`
+	MaxConns int `name:"max_conns" default:"10"`
+}
+
+func (p Pool) Validate() error {
+	if p.MinConns > p.MaxConns {
+		return fmt.Errorf("min_conns %v is greater than max_conns %v", p.MinConns, p.MaxConns)
+	}
+	return nil
+}
+
+func ExampleParser_RegisterGroupValidator() {
+	type TLS struct {
+		Cert string
+		Key  string
+	}
+
+	p := argparse.NewParser().SetPanic(false).SetCollectErrors(true)
+	p.RegisterGroupValidator("TLS", func(v interface{}) error {
+		if tls := v.(*TLS); (tls.Cert == "") != (tls.Key == "") {
+			return errors.New("cert and key must be both set")
+		}
+		return nil
+	})
+
+	pool := Pool{}
+	tls := TLS{}
+	p.Register(&pool)
+	p.Register(&tls)
+	fmt.Println(p.Parse(strings.Split("-pool_min_conns 20 -tls_cert a.pem", " ")))
+
+	// Output:
+	// Failed to validate the group[Pool]: [Validate] min_conns 20 is greater than max_conns 10
+	// Failed to validate the group[TLS]: [RegisterGroupValidator] cert and key must be both set
+}
+
+func ExampleParser_constraint() {
+	type Default struct {
+		JSON bool `name:"json" exclusive:"output"`
+		YAML bool `name:"yaml" exclusive:"output"`
+	}
+
+	type TLS struct {
+		Cert string `together:"tls"`
+		Key  string `together:"tls"`
+	}
+
+	p := argparse.NewParser().SetPanic(false).SetCollectErrors(true)
+	p.Register(&Default{})
+	p.Register(&TLS{})
+	fmt.Println(p.Parse(strings.Split("-json -yaml -tls_cert a.pem", " ")))
+
+	// Output:
+	// The options in the set[output] are mutually exclusive: -json, -yaml
+	// The options in the set[tls] must be given together, but missing: -tls_key
+}
+
+func ExampleParser_positional() {
+	type Copy struct {
+		Force  bool
+		Source string        `pos:"0" strategy:"required"`
+		Dest   string        `pos:"1" default:"."`
+		Files  []string      `pos:"rest"`
+		Wait   time.Duration `pos:"2" default:"1s"`
+	}
+
+	p := argparse.NewParser().SetPanic(false)
+	cp := Copy{}
+
+	p.Register(&cp)
+	p.Parse(strings.Split("-copy_force a.txt /tmp 3s b.txt c.txt", " "))
+	fmt.Printf("%+v\n", cp)
+
+	p = argparse.NewParser().SetPanic(false)
+	p.Register(&Copy{})
+	fmt.Println(p.Parse(strings.Split("-copy_force", " ")))
+
+	// If there are the subcommands, the positional arguments are not bound.
+	p = argparse.NewParser().SetPanic(false)
+	p.Register(&Copy{})
+	p.AddCommand("sync", "Sync the files")
+	fmt.Println(p.Parse(strings.Split("-copy_force sync", " ")))
+
+	// Output:
+	// {Force:true Source:a.txt Dest:/tmp Files:[b.txt c.txt] Wait:3s}
+	// Missing the required options: SOURCE
+	// <nil>
+}
+
+func ExampleParser_WriteCompletion() {
+	type Default struct {
+		Verbose bool   `short:"v" help:"verbose output"`
+		Format  string `validate:"str_array(json,yaml)" help:"the output format"`
+		Config  string `complete:"file" help:"the config file"`
+		Workdir string `complete:"dir" help:"the working directory"`
+	}
+
+	p := argparse.NewParser().SetGNU(true)
+	p.Register(&Default{})
+	p.WriteCompletion(os.Stdout, "fish")
+
+	// Output:
+	// # fish completion for argparse.test
+	//
+	// complete -c argparse.test -l verbose -s v -d 'verbose output'
+	// complete -c argparse.test -l format -d 'the output format' -r -f -a 'json yaml'
+	// complete -c argparse.test -l config -d 'the config file' -r -F
+	// complete -c argparse.test -l workdir -d 'the working directory' -r -f -a '(__fish_complete_directories)'
+}
+
+func ExampleParser_WriteHelp() {
+	type Server struct {
+		Addr string `default:"0.0.0.0" help:"the address to listen to" env:"ADDR"`
+		Port int    `short:"p" default:"80" help:"the port to listen to" validate:"num_range(1,65535)"`
+		Mode string `default:"debug" validate:"str_array(debug,release)"`
+	}
+
+	p := argparse.NewParser().SetHelpWidth(64)
+	p.SetHelpHeader("app v1.0").SetHelpFooter("Examples:\n  app -p 8080")
+	p.SetGroupDescription("Server", "The options of the server")
+	p.Register(&Server{})
+	p.WriteHelp(os.Stdout)
+
+	// Output:
+	// app v1.0
+	//
+	// Usage: argparse.test [OPTIONS]
+	//
+	// Server: The options of the server
+	//   -server_addr string
+	//         the address to listen to (default: 0.0.0.0; env: ADDR)
+	//   -p, -server_port int
+	//         the port to listen to (default: 80; range: [1, 65535])
+	//   -server_mode string
+	//         (default: debug; values: debug|release)
+	//
+	// Examples:
+	//   app -p 8080
+}
+
+func ExampleParser_WriteMarkdown() {
+	type Server struct {
+		Addr string `default:"0.0.0.0" help:"the address to listen to"`
+		Port int    `short:"p" default:"80" help:"the port to listen to" validate:"num_range(1,65535)"`
+	}
+
+	p := argparse.NewParser()
+	p.SetGroupDescription("Server", "The options of the server")
+	p.Register(&Server{})
+	p.WriteMarkdown(os.Stdout)
+
+	// Output:
+	// # argparse.test
+	//
+	// ```
+	// argparse.test [OPTIONS]
+	// ```
+	//
+	// ## Server
+	//
+	// The options of the server
+	//
+	// | Option | Type | Description |
+	// |---|---|---|
+	// | `-server_addr` | string | the address to listen to (default: 0.0.0.0) |
+	// | `-p`, `-server_port` | int | the port to listen to (default: 80; range: [1, 65535]) |
+}
+
+func ExampleParser_suggest() {
+	type Group struct {
+		Float32 float32
+	}
+
+	p := argparse.NewParser().SetPanic(false)
+	p.Register(&Group{})
+	fmt.Println(p.Parse(strings.Split("-group_fload32 2.5", " ")))
+
+	// Output:
+	// unknown option -group_fload32; did you mean -group_float32?
+}
+
+func ExampleParser_negated() {
+	type Log struct {
+		Color bool `default:"true" help:"colorize the log"`
+		Debug bool
+	}
+
+	p := argparse.NewParser()
+	log := Log{}
+	p.Register(&log)
+	p.Parse(strings.Split("-no_log_color -log_debug", " "))
+	fmt.Printf("%+v\n", log)
+
+	p = argparse.NewParser().SetGNU(true)
+	log = Log{}
+	p.Register(&log)
+	p.Parse(strings.Split("--no-log_color=false --no-log_debug", " "))
+	fmt.Printf("%+v\n", log)
+
+	// The negated form is skipped if there is the option with the same name,
+	// and it doesn't choose the option from the exclusive set.
+	type Default struct {
+		Verbose   bool
+		NoVerbose string `name:"no_verbose"`
+		JSON      bool   `name:"json" exclusive:"output"`
+		YAML      bool   `name:"yaml" exclusive:"output"`
+	}
+
+	p = argparse.NewParser().SetPanic(false)
+	_default := Default{}
+	p.Register(&_default)
+	err := p.Parse(strings.Split("-json -no_yaml -no_verbose quiet", " "))
+	fmt.Printf("%v %+v\n", err, _default)
+
+	// Output:
+	// {Color:false Debug:true}
+	// {Color:true Debug:false}
+	// <nil> {Verbose:false NoVerbose:quiet JSON:true YAML:false}
+}
+
+func ExampleParser_Sources() {
+	type Server struct {
+		Addr string `default:"0.0.0.0"`
+		Port int    `default:"80" env:"ARGPARSE_EXAMPLE_PORT"`
+		Tags []string
+		Name string
+	}
+
+	os.Setenv("ARGPARSE_EXAMPLE_PORT", "8080")
+	defer os.Unsetenv("ARGPARSE_EXAMPLE_PORT")
+
+	p := argparse.NewParser()
+	p.Register(&Server{})
+	p.LoadINI(strings.NewReader("[server]\ntags = a\ntags = b\n"))
+	p.Parse(strings.Split("-server_name app", " "))
+	for _, s := range p.Sources() {
+		fmt.Printf("%v.%v: %v %q %v\n", s.Group, s.Field, s.From, s.Where, s.Raw)
+	}
+
+	// Output:
+	// Server.Addr: default "" [0.0.0.0]
+	// Server.Port: env "ARGPARSE_EXAMPLE_PORT" [8080]
+	// Server.Tags: file "" [a b]
+	// Server.Name: command "" [app]
+}
+
+func ExampleParser_Dump() {
+	type Server struct {
+		Addr     string   `default:"0.0.0.0"`
+		Port     int      `default:"80"`
+		Tags     []string `sep:","`
+		Password string   `strategy:"secret"`
+	}
+
+	p := argparse.NewParser()
+	p.Register(&Server{})
+	p.Parse(strings.Split("-server_port 8080 -server_tags a,b -server_password pw", " "))
+	p.Dump(os.Stdout, "flag")
+	p.Dump(os.Stdout, "json")
+	p.Dump(os.Stdout, "ini")
+
+	// The dump in the GNU style can be given back in the GNU style.
+	type Default struct {
+		X    int
+		Name string
+	}
+
+	p = argparse.NewParser().SetGNU(true)
+	p.Register(&Default{})
+	p.Parse(strings.Split("--x 7 --name a", " "))
+	buf := bytes.NewBuffer(nil)
+	p.Dump(buf, "flag")
+	fmt.Print(buf.String())
+
+	p = argparse.NewParser().SetGNU(true)
+	_default := Default{}
+	p.Register(&_default)
+	p.Parse(strings.Fields(buf.String()))
+	fmt.Printf("%+v\n", _default)
+
+	// Output:
+	// -server_addr=0.0.0.0 -server_port=8080 -server_tags=a -server_tags=b '-server_password=******'
+	// {
+	//   "server": {
+	//     "addr": "0.0.0.0",
+	//     "port": 8080,
+	//     "tags": ["a", "b"],
+	//     "password": "******"
+	//   }
+	// }
+	// [server]
+	// addr = 0.0.0.0
+	// port = 8080
+	// tags = a
+	// tags = b
+	// password = ******
+	// --x=7 --name=a
+	// {X:7 Name:a}
+}
+
+func ExampleParser_secret() {
+	type DB struct {
+		Password string `strategy:"secret" validate:"str_len(8,64)"`
+	}
+
+	p := argparse.NewParser().SetPanic(false)
+	p.Register(&DB{})
+	fmt.Println(p.Parse(strings.Split("-db_password abc", " ")))
+	s, _ := p.Source("DB", "Password")
+	fmt.Println(s.From, s.Raw)
+
+	file, _ := os.CreateTemp("", "password")
+	file.WriteString("p@ssw0rd-from-file\n")
+	file.Close()
+	defer os.Remove(file.Name())
+
+	p = argparse.NewParser()
+	db := DB{}
+	p.Register(&db)
+	p.Parse([]string{"-db_password_file", file.Name()})
+	fmt.Println(db.Password)
+
+	type S struct {
+		PW  string `strategy:"secret" validate:"str_array(x,y)"`
+		Pin int    `strategy:"secret"`
+	}
+
+	p = argparse.NewParser().SetPanic(false)
+	p.Register(&S{})
+	fmt.Println(p.Parse(strings.Split("-s_pw e", " ")))
+
+	p = argparse.NewParser().SetPanic(false)
+	p.Register(&S{})
+	fmt.Println(p.Parse(strings.Split("-s_pin e", " ")))
+
+	// Output:
+	// Failed to validate the field[DB.Password]: [str_len(8,64)] the secret value is invalid
+	// command [******]
+	// p@ssw0rd-from-file
+	// Failed to validate the field[S.PW]: [str_array(x,y)] the secret value is invalid
+	// Failed to parse the option[s_pin] with the value[******]: the secret value is invalid
+}
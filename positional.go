@@ -0,0 +1,125 @@
+package argparse
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const posRest = "rest"
+
+// Register the positional argument, which is bound to the field by the tag, pos.
+func (p *Parser) register_positional(name, fname, pos, _default, usage, gname string,
+	field reflect.StructField) {
+	index := -1
+	if pos != posRest {
+		i, err := strconv.Atoi(pos)
+		if err != nil || i < 0 {
+			panic(fmt.Sprintf("The position of the positional argument[%v] is invalid: %v",
+				name, pos))
+		}
+		index = i
+	} else if field.Type.Kind() != reflect.Slice {
+		panic(fmt.Sprintf("The rest positional argument[%v] is not a slice", name))
+	}
+
+	value, ok := p.newValue(name, _default, field)
+	if !ok {
+		Debugf("Don't support the type, %v, so skip to register the positional argument: %v.%v",
+			field.Type.String(), gname, field.Name)
+		return
+	}
+
+	Debugf("Registering the positional argument: name[%v] pos[%v]", name, pos)
+	p.group[name] = value
+	p.options = append(p.options, &option{
+		name:    name,
+		group:   gname,
+		field:   field.Name,
+		help:    usage,
+		typ:     field.Type,
+		tag:     field.Tag,
+		pos:     pos,
+		index:   index,
+		metavar: strings.ToUpper(fname),
+	})
+}
+
+// Return the positional arguments sorted by the position, and the rest at last.
+func (p *Parser) positionals() []*option {
+	var opts []*option
+	for _, opt := range p.options {
+		if opt.pos != "" {
+			opts = append(opts, opt)
+		}
+	}
+
+	sort.SliceStable(opts, func(i, j int) bool {
+		if opts[j].index < 0 {
+			return opts[i].index >= 0
+		}
+		return opts[i].index >= 0 && opts[i].index < opts[j].index
+	})
+	return opts
+}
+
+// Bind the positional arguments to the fields.
+//
+// If there are the subcommands, the positional arguments are not bound,
+// because the first is the name of the subcommand.
+func (p *Parser) parsePositional() {
+	if len(p.commands) > 0 {
+		return
+	}
+
+	args := p.Args()
+	rest := 0
+	for _, opt := range p.positionals() {
+		if opt.index >= 0 {
+			if opt.index+1 > rest {
+				rest = opt.index + 1
+			}
+			if opt.index < len(args) {
+				p.setPositional(opt, args[opt.index])
+			}
+			continue
+		}
+
+		for i := rest; i < len(args); i++ {
+			p.setPositional(opt, args[i])
+		}
+	}
+}
+
+func (p *Parser) setPositional(opt *option, arg string) {
+	value := p.group[opt.name].(flag.Value)
+	if err := value.Set(arg); err != nil {
+		if hasStrategy(opt.tag, STRATEGY_SECRET) {
+			arg, err = secretMask, maskError(err)
+		}
+		p.fail(&ParseError{Option: opt.metavar, Value: arg, Err: err})
+		return
+	}
+	p.posset[opt.name] = true
+	p.record(opt.name, arg)
+}
+
+// Return the usage of the positional arguments, such as "SOURCE [DEST] [REST...]".
+// The optional argument is in the brackets.
+func (p *Parser) positionalUsage() string {
+	var ss []string
+	for _, opt := range p.positionals() {
+		s := opt.metavar
+		if opt.index < 0 {
+			s += "..."
+		}
+		if !hasStrategy(opt.tag, STRATEGY_REQUIRED) {
+			s = "[" + s + "]"
+		}
+		ss = append(ss, s)
+	}
+	return strings.Join(ss, " ")
+}
@@ -22,6 +22,7 @@ import (
 // it through the tag of `validate:"validate_num_range"`. min and max are given by
 // `min:"MIN_VALUE" max:"MAX_VALUE"`. min or max or both maybe been omitted.
 // If either is been omitted, it is considered to pass the validation.
+// They may be also given inline, such as `validate:"num_range(1,65535)"`.
 func ValidateNumberRange(tag string, value interface{}) error {
 	min := TagGet(tag, "min")
 	max := TagGet(tag, "max")
@@ -88,4 +89,5 @@ func ValidateNumberRange(tag string, value interface{}) error {
 
 func init() {
 	RegisterValidator("validate_num_range", ValidateNumberRange)
+	RegisterValidatorParams("validate_num_range", "min", "max")
 }
@@ -0,0 +1,100 @@
+package argparse
+
+import (
+	"flag"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// The mask of the value of the secret option.
+const secretMask = "******"
+
+// The suffix of the companion of the secret option, such as "db_password_file",
+// which reads the value of the option from the file.
+const fileSuffix = "_file"
+
+// Return the value to be output, which is masked if the option is secret
+// and the value is not the empty string.
+func maskValue(tag reflect.StructTag, value interface{}) interface{} {
+	if s, ok := value.(string); ok && s == "" {
+		return value
+	} else if hasStrategy(tag, STRATEGY_SECRET) {
+		return secretMask
+	}
+	return value
+}
+
+// Replace the quoted secret in s with the quoted mask, such as the value
+// in "invalid value "abc" for flag -password", which is output by the flag set.
+//
+// Only the quoted form is replaced, so that the other words in s,
+// which may contain the secret, are kept.
+func maskString(s, secret string) string {
+	return strings.Replace(s, strconv.Quote(secret), strconv.Quote(secretMask), -1)
+}
+
+// Return true if the option, or the short name, is secret.
+func (p *Parser) isSecret(name string) bool {
+	for _, opt := range p.options {
+		if opt.name == name || (opt.short != "" && opt.short == name) {
+			return hasStrategy(opt.tag, STRATEGY_SECRET)
+		}
+	}
+	return false
+}
+
+// Return true if the option has the companion to read its value from the file.
+func (p *Parser) hasFile(name string) bool {
+	if f := p.flagSet.Lookup(name + fileSuffix); f != nil {
+		_, ok := f.Value.(*fileValue)
+		return ok
+	}
+	return false
+}
+
+// secretError hides the secret value by replacing the message of the error.
+type secretError struct {
+	err error
+	msg string
+}
+
+func (e secretError) Error() string {
+	return e.msg
+}
+
+func (e secretError) Unwrap() error {
+	return e.err
+}
+
+// Replace the message of the error, which may contain the secret value
+// in any form, such as "The value[abc] is not in [x y]", with a generic one.
+func maskError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return secretError{err: err, msg: "the secret value is invalid"}
+}
+
+// fileValue is the companion of the secret option, such as -db_password_file,
+// which reads the value of the option from the file. The trailing newlines
+// of the file are trimmed.
+type fileValue struct {
+	value flag.Value
+	path  string
+}
+
+func (v *fileValue) String() string {
+	return v.path
+}
+
+func (v *fileValue) Set(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	v.path = path
+	return v.value.Set(strings.TrimRight(string(data), "\r\n"))
+}
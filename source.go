@@ -0,0 +1,137 @@
+package argparse
+
+import (
+	"flag"
+)
+
+// The sources of the value of the option.
+const (
+	SOURCE_DEFAULT = "default" // The default value given by the tag
+	SOURCE_FILE    = "file"    // The configuration file loaded by LoadFile, LoadJSON or LoadINI
+	SOURCE_ENV     = "env"     // The environment variable
+	SOURCE_COMMAND = "command" // The command line
+)
+
+// Source is the information where the final value of the option comes from.
+type Source struct {
+	Group  string // The name of the group
+	Field  string // The name of the field
+	Option string // The name of the option, or the positional argument, such as "SOURCE"
+	From   string // One of SOURCE_DEFAULT, SOURCE_FILE, SOURCE_ENV and SOURCE_COMMAND
+
+	// The name of the environment variable for SOURCE_ENV, or the path of
+	// the configuration file for SOURCE_FILE if loaded by LoadFile.
+	Where string
+
+	// The raw strings supplied by the source, which has more than one for
+	// the repeatable option, such as the slice and the map. For SOURCE_DEFAULT,
+	// it's the default value given by the tag, or empty if not given.
+	// They are masked if the option has the strategy, secret.
+	Raw []string
+}
+
+// Return the source of the final value of the field of the group, which is
+// the name of the registered struct, or the name of the sub-group, such as
+// "server_db". It should be called after parsing.
+//
+// Return false if the field isn't registered as an option.
+func (p *Parser) Source(group, field string) (Source, bool) {
+	for _, opt := range p.options {
+		if opt.group == group && opt.field == field {
+			return p.source(opt), true
+		}
+	}
+	return Source{}, false
+}
+
+// Return the sources of the final values of all the options,
+// in the order of registering. See Source.
+func (p *Parser) Sources() []Source {
+	sources := make([]Source, len(p.options))
+	for i, opt := range p.options {
+		sources[i] = p.source(opt)
+	}
+	return sources
+}
+
+func (p *Parser) source(opt *option) Source {
+	name := opt.name
+	if opt.pos != "" {
+		name = opt.metavar
+	}
+
+	s := Source{Group: opt.group, Field: opt.field, Option: name, From: SOURCE_DEFAULT}
+	if _s, ok := p.sources[opt.name]; ok {
+		s.From, s.Where = _s.From, _s.Where
+		s.Raw = append([]string(nil), _s.Raw...)
+	} else if _default := opt.tag.Get(TAG_DEFAULT); _default != "" {
+		s.Raw = []string{_default}
+	}
+
+	if hasStrategy(opt.tag, STRATEGY_SECRET) {
+		for i := range s.Raw {
+			s.Raw[i] = secretMask
+		}
+	}
+	return s
+}
+
+// Set where the values of the options will come from.
+func (p *Parser) setSource(from, where string) {
+	p.from, p.where = from, where
+}
+
+// Record the raw string setting the option from the current source.
+//
+// The repeatable option keeps the strings from the same source,
+// and the others keep only the last.
+func (p *Parser) record(name, raw string) {
+	s, ok := p.sources[name]
+	if !ok || s.From != p.from || s.Where != p.where {
+		s = &Source{From: p.from, Where: p.where}
+		p.sources[name] = s
+	}
+
+	if _, ok := p.group[name].(resetter); ok {
+		s.Raw = append(s.Raw, raw)
+	} else {
+		s.Raw = []string{raw}
+	}
+}
+
+// sourceValue records the source of the value of the option when it's set.
+type sourceValue struct {
+	flag.Value
+	name   string
+	parser *Parser
+}
+
+func (v sourceValue) Set(s string) error {
+	if err := v.Value.Set(s); err != nil {
+		// The flag set outputs the value in the error, so remember it to be masked.
+		if v.parser.isSecret(v.name) {
+			v.parser.failedSecret = s
+			s, err = secretMask, maskError(err)
+		}
+		v.parser.failed = &ParseError{Option: v.name, Value: s, Err: err}
+		return err
+	}
+	v.parser.record(v.name, s)
+	return nil
+}
+
+func (v sourceValue) Get() interface{} {
+	if g, ok := v.Value.(flag.Getter); ok {
+		return g.Get()
+	}
+	return v.Value.String()
+}
+
+func (v sourceValue) IsBoolFlag() bool {
+	if b, ok := v.Value.(interface {
+		IsBoolFlag() bool
+	}); ok {
+		return b.IsBoolFlag()
+	}
+	return false
+}
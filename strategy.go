@@ -9,6 +9,16 @@ import (
 const (
 	// If there is this strategy in a certain option, don't register it.
 	STRATEGY_SKIP = "skip"
+
+	// If there is this strategy in a certain option, it must be set by one of
+	// the command line, the environment variable and the configuration file.
+	// The default value given by the tag is not regarded as being set.
+	STRATEGY_REQUIRED = "required"
+
+	// If there is this strategy in a certain option, its value is sensitive,
+	// such as the password, and is masked when being output. Besides, it can be
+	// read from the file by the companion option, such as -db_password_file.
+	STRATEGY_SECRET = "secret"
 )
 
 func checkStrategy(node, sets string) bool {
@@ -30,3 +40,7 @@ func validStrategy(tag reflect.StructTag) bool {
 
 	return true
 }
+
+func hasStrategy(tag reflect.StructTag, strategy string) bool {
+	return checkStrategy(strategy, strings.TrimSpace(tag.Get(TAG_STRATEGY)))
+}
@@ -42,7 +42,8 @@ func ValidateStrNotEmpty(tag string, value interface{}) error {
 // by `min:"MIN_VALUE" max:"MAX_VALUE"`, which are converted to the integers
 // based on the base 10. If failed to convert, return an error.
 // min or max or both maybe been omitted. If either is been omitted,
-// it is considered to pass the validation.
+// it is considered to pass the validation. They may be also given inline,
+// such as `validate:"str_len(1,64)"`.
 //
 // Notice: the leading and tail whitespaces of the value will be trimed down,
 // then calculate.
@@ -79,7 +80,8 @@ func ValidateStrLen(tag string, value interface{}) error {
 //
 // This validation has been registered as "validate_str_regexp". so you can use
 // it through the tag of `validate:"validate_str_regexp"`. The pattern is acquired
-// by the tag `pattern:"PATTERN"`. The validation way is regexp.MatchString().
+// by the tag `pattern:"PATTERN"`, or given inline, such as
+// `validate:"str_regexp(^[a-z]+$)"`. The validation way is regexp.MatchString().
 //
 // Notice: the leading and tail whitespaces of the value will be trimed down,
 // then calculate.
@@ -154,7 +156,7 @@ func ValidateIP6(tag string, value interface{}) error {
 // Validate whether the value is in the string array came from the tag of array.
 //
 // when using this validtor, you should give the tag, array, which is separated
-// by the comma.
+// by the comma, or give it inline, such as `validate:"str_array(json,yaml)"`.
 //
 // It's registered as "validate_str_array".
 func ValidateStrArray(tag string, value interface{}) error {
@@ -179,6 +181,10 @@ func init() {
 	RegisterValidator("validate_digit", ValidateDigit)
 	RegisterValidator("validate_str_array", ValidateStrArray)
 
+	RegisterValidatorParams("validate_str_len", "min", "max")
+	RegisterValidatorParams("validate_str_regexp", "pattern")
+	RegisterValidatorParams("validate_str_array", "array")
+
 	RegisterValidator("validate_ip", ValidateIP)
 	RegisterValidator("validate_ip4", ValidateIP4)
 	RegisterValidator("validate_ip6", ValidateIP6)
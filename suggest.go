@@ -0,0 +1,107 @@
+package argparse
+
+import (
+	"bytes"
+	"strings"
+)
+
+// The prefix of the error returned by the flag set for the unknown option.
+const unknownFlagPrefix = "flag provided but not defined: -"
+
+// Parse the arguments by the flag set in the style of the flag package.
+//
+// The output of the flag set is buffered, so that the unknown option is
+// reported with the suggestion, like the GNU style.
+func (p *Parser) parseFlags(args []string) {
+	out := p.flagSet.Output()
+	buf := bytes.NewBuffer(nil)
+	p.flagSet.SetOutput(buf)
+	p.failed, p.failedSecret = nil, ""
+	err := p.flagSet.Parse(args)
+	p.flagSet.SetOutput(out)
+
+	// The flag set outputs the value of the secret option failing to be set.
+	if err != nil && p.failedSecret != "" {
+		buf = bytes.NewBufferString(maskString(buf.String(), p.failedSecret))
+	}
+
+	if err != nil && strings.HasPrefix(err.Error(), unknownFlagPrefix) {
+		p.failUnknown("-", strings.TrimPrefix(err.Error(), unknownFlagPrefix))
+	}
+
+	out.Write(buf.Bytes())
+	if err != nil && p.failed != nil {
+		p.abort(p.failed)
+	} else if err != nil {
+		p.abort(&ParseError{Err: err})
+	}
+}
+
+// Output the error of the unknown option with the suggestion and the usage,
+// then abort.
+func (p *Parser) failUnknown(prefix, name string) {
+	if s := p.suggest(name); s != "" {
+		p.failf("unknown option %s%s; did you mean %s?", prefix, name, s)
+	}
+	p.failf("unknown option %s%s", prefix, name)
+}
+
+// Return the registered option with the prefix, which is the most similar
+// to name by the edit distance, such as "-group_float32" for "group_fload32".
+// The negated forms of the bool options are also considered.
+//
+// Return "" if there is no similar one.
+func (p *Parser) suggest(name string) string {
+	if len(name) < 2 {
+		return ""
+	}
+
+	// Allow about one typo per three letters.
+	best, min := "", len(name)/3+2
+	for _, opt := range p.options {
+		if opt.pos != "" {
+			continue
+		}
+
+		for _, s := range []string{p.flagName(opt.name), p.negatedName(opt.name)} {
+			if s == "" {
+				continue
+			}
+			if d := editDistance(name, strings.TrimLeft(s, "-")); d < min {
+				best, min = s, d
+			}
+		}
+	}
+	return best
+}
+
+// Return the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(first int, others ...int) int {
+	for _, v := range others {
+		if v < first {
+			first = v
+		}
+	}
+	return first
+}
@@ -1,6 +1,7 @@
 package argparse
 
 import (
+	"flag"
 	"fmt"
 	"reflect"
 )
@@ -42,3 +43,24 @@ func getFromTag(tag reflect.StructTag, key, default_ string) string {
 	}
 	return v
 }
+
+// Get the value of the option registered in the parser.
+//
+// If v implements flag.Getter, return v.Get(). Or v must be a pointer,
+// and return the value that it points to.
+func getValue(v interface{}) interface{} {
+	if getter, ok := v.(flag.Getter); ok {
+		return getter.Get()
+	}
+	return reflect.ValueOf(v).Elem().Interface()
+}
+
+// Return true if the string slice ss contains s.
+func contains(ss []string, s string) bool {
+	for _, _s := range ss {
+		if _s == s {
+			return true
+		}
+	}
+	return false
+}
@@ -4,12 +4,15 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 )
 
 var (
-	validatorError = errors.New("The validation function doesn't exist")
-	validators     = make(tValidation)
+	validatorError  = errors.New("The validation function doesn't exist")
+	validators      = make(tValidation)
+	validatorParams = make(map[string][]string)
 )
 
 type Validation interface {
@@ -21,65 +24,296 @@ type Validation interface {
 	Validate(string, interface{}) error
 }
 
+// ArgsValidation is the validator whose arguments are given inline in the tag
+// of validate, such as `validate:"name(arg1,arg2)"`.
+type ArgsValidation interface {
+	// Validate the second argument, which is the value of the current field,
+	// by the first, which is the arguments separated by the comma. If no
+	// arguments are given, the first argument is nil.
+	//
+	// Return nil if validating successfully, or an error, which is the reason
+	// for failure.
+	ValidateArgs([]string, interface{}) error
+}
+
 // Register a validator to validate whether the value of the field is valid.
 //
 // The first argument is the name of the validator, which must be unique. The
 // second is the validator, which is either a type implementing the interface
-// Validation or a funciton whose type is the same as the method of Validate of
-// the interface Validation.
+// Validation or ArgsValidation, or a funciton whose type is the same as the
+// method of Validate of the interface Validation or ValidateArgs of ArgsValidation.
+//
+// The validator can be used by the name with the prefix, "validate_", omitted,
+// and with the arguments given inline, such as `validate:"num_range(1,65535)"`.
+// The inline arguments are passed to ArgsValidation directly, or passed to
+// Validation by the tag if its parameters are registered by
+// RegisterValidatorParams.
 //
 // Return true if registering successfully. Return false if having been registered.
 // If the validator is invalid, it will panic.
 func RegisterValidator(name string, validator interface{}) bool {
-	if _, ok := validators[name]; ok {
+	return validators.register(name, validator)
+}
+
+// Register a validator only for this parser and its subcommands, which shadows
+// the global one with the same name registered by RegisterValidator.
+// See RegisterValidator.
+func (p *Parser) RegisterValidator(name string, validator interface{}) bool {
+	return p.validators.register(name, validator)
+}
+
+// Return the global validator registered by RegisterValidator.
+//
+// Return nil if it doesn't exist.
+func GetValidator(name string) interface{} {
+	return validators[name]
+}
+
+// Return the validator used by this parser, which is looked up in this parser,
+// its parents if it's a subcommand, and the global in turn.
+//
+// Return nil if it doesn't exist.
+func (p *Parser) GetValidator(name string) interface{} {
+	for ; p != nil; p = p.parent {
+		if validator, ok := p.validators[name]; ok {
+			return validator
+		}
+	}
+	return validators[name]
+}
+
+// Return the sorted names of all the global validators.
+func Validators() []string {
+	return validators.names(nil)
+}
+
+// Return the sorted names of all the validators used by this parser, including
+// the ones registered in this parser, its parents and the global.
+func (p *Parser) Validators() []string {
+	names := validators.names(nil)
+	for ; p != nil; p = p.parent {
+		names = p.validators.names(names)
+	}
+	return names
+}
+
+type tValidation map[string]interface{}
+
+func (t tValidation) register(name string, validator interface{}) bool {
+	if _, ok := t[name]; ok {
 		return false
 	}
 
-	if _, ok := validator.(Validation); !ok {
-		if _, ok := validator.(func(string, interface{}) error); !ok {
-			panic("The validator is invalid")
-		}
+	switch validator.(type) {
+	case Validation, func(string, interface{}) error:
+	case ArgsValidation, func([]string, interface{}) error:
+	default:
+		panic("The validator is invalid")
 	}
 
-	validators[name] = validator
+	t[name] = validator
 	return true
 }
 
-type tValidation map[string]interface{}
+// Register the names of the parameters of the validator, which are the keys
+// in the tag, from which the validator implementing Validation reads its
+// parameters, such as "min" and "max" of "validate_num_range".
+//
+// When the arguments are given inline, such as `validate:"num_range(1,65535)"`,
+// they are passed to the validator as the tag, such as `min:"1" max:"65535"`,
+// which override the keys in the tag of the field. If the validator has only
+// one parameter, the whole arguments are its value, such as the pattern of
+// `validate:"str_regexp(^[a-z]{1,3}$)"`. Or the last parameter gets the rest
+// arguments.
+func RegisterValidatorParams(name string, params ...string) {
+	validatorParams[name] = params
+}
+
+// Add the names of the validators into names if they don't exist, and sort them.
+func (t tValidation) names(names []string) []string {
+	for name := range t {
+		if !contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
 
-func (t tValidation) call(tag reflect.StructTag, name string, value interface{}) (err error) {
+// Call the validator. args is the inline arguments, which is nil
+// if the arguments are not given.
+func callValidator(validator interface{}, name string, tag reflect.StructTag, args *string,
+	value interface{}) (err error) {
 	defer func() {
 		if _err := recover(); _err != nil {
 			err = errors.New(fmt.Sprintf("panic: %v", _err))
 		}
 	}()
 
-	if validator, ok := t[name]; ok {
-		if validation, ok := validator.(Validation); ok {
-			return validation.Validate(string(tag), value)
-		} else if validation, ok := validator.(func(string, interface{}) error); ok {
-			return validation(string(tag), value)
+	var _args []string
+	if args != nil {
+		_args = splitArgs(*args)
+	}
+
+	switch validation := validator.(type) {
+	case ArgsValidation:
+		return validation.ValidateArgs(_args, value)
+	case func([]string, interface{}) error:
+		return validation(_args, value)
+	}
+
+	if args != nil {
+		params := validatorParams[name]
+		if len(params) == 0 {
+			return errors.New("The validator doesn't accept the arguments")
 		}
+
+		_args = paramArgs(params, *args)
+		ts := make([]string, 0, len(params)+1)
+		for i, arg := range _args {
+			ts = append(ts, fmt.Sprintf("%v:%v", params[i], strconv.Quote(arg)))
+		}
+		tag = reflect.StructTag(strings.Join(append(ts, string(tag)), " "))
+	}
+
+	switch validation := validator.(type) {
+	case Validation:
+		return validation.Validate(string(tag), value)
+	case func(string, interface{}) error:
+		return validation(string(tag), value)
 	}
 
 	return validatorError
 }
 
-func (t tValidation) Validate(tag reflect.StructTag, value interface{}) error {
+// Map the inline arguments onto the parameters. If there is only one parameter,
+// the whole arguments are its value. Or the last parameter gets the rest arguments.
+func paramArgs(params []string, args string) []string {
+	if len(params) == 1 {
+		return []string{args}
+	}
+
+	_args := splitArgs(args)
+	if len(_args) > len(params) {
+		n := len(params) - 1
+		_args = append(_args[:n], strings.Join(_args[n:], ","))
+	}
+	return _args
+}
+
+// Split the string by the comma, which is not in the parentheses.
+func splitArgs(s string) []string {
+	var args []string
+	depth, start := 0, 0
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				args = append(args, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(args, strings.TrimSpace(s[start:]))
+}
+
+// Parse the validator given by the tag, such as "name" or "name(args)".
+//
+// Return the name and the arguments, which is nil if not given.
+func parseValidator(s string) (name string, args *string) {
+	if index := strings.Index(s, "("); index > 0 && s[len(s)-1] == ')' {
+		_args := s[index+1 : len(s)-1]
+		return strings.TrimSpace(s[:index]), &_args
+	}
+	return s, nil
+}
+
+// Validate the value by the validators given by the tag in turn.
+//
+// Return nil if all passing. Or return *ValidationError, only the validator,
+// the value and the cause of which are set.
+func (p *Parser) validate(tag reflect.StructTag, value interface{}) *ValidationError {
 	validation := strings.TrimSpace(tag.Get(TAG_VALIDATE))
 
-	for _, name := range strings.Split(validation, ",") {
-		if name = strings.TrimSpace(name); name == "" {
+	for _, s := range splitArgs(validation) {
+		if s == "" {
 			continue
 		}
-		if err := t.call(tag, name, value); err != nil {
-			return errors.New(fmt.Sprintf("[%v] %v", name, err))
+
+		name, args := parseValidator(s)
+		validator := p.GetValidator(name)
+		if validator == nil && !strings.HasPrefix(name, "validate_") {
+			if validator = p.GetValidator("validate_" + name); validator != nil {
+				name = "validate_" + name
+			}
+		}
+
+		if err := callValidator(validator, name, tag, args, value); err != nil {
+			return &ValidationError{Validator: s, Value: value, Err: err}
 		}
 	}
 
 	return nil
 }
 
+// Register a function to validate the group, which is called with the pointer
+// to the struct of the group after all the groups are set. It's used to check
+// the invariants across the fields, such as "min_conns <= max_conns".
+//
+// group is the name of the group, which is the name of the registered struct,
+// or the name of the sub-group, such as "server_db". Besides, if the struct
+// has the method, "Validate() error", it's also called.
+func (p *Parser) RegisterGroupValidator(group string, validator func(interface{}) error) *Parser {
+	p.gvalidators[group] = append(p.gvalidators[group], validator)
+	return p
+}
+
+// Validate all the registered groups by RegisterGroupValidator
+// and the method, "Validate() error".
+func (p *Parser) validateGroups() {
+	for _, name := range p.gnames {
+		p.validateGroup(name, reflect.ValueOf(p.cache[name]).Elem())
+	}
+}
+
+// Validate the sub-groups, then the group.
+//
+// The anonymous struct is not validated by itself, because its method,
+// Validate, is promoted to the group.
+func (p *Parser) validateGroup(gname string, group reflect.Value) {
+	tg := group.Type()
+	for i, num := 0, group.NumField(); i < num; i++ {
+		field := tg.Field(i)
+		if field.Anonymous || field.PkgPath != "" || !validStrategy(field.Tag) ||
+			!p.isGroup(field.Type) {
+			continue
+		}
+
+		fname := getFromTag(field.Tag, TAG_NAME, field.Name)
+		p.validateGroup(p.getName(gname, fname), group.Field(i))
+	}
+
+	value := group.Addr().Interface()
+	if v, ok := value.(interface {
+		Validate() error
+	}); ok {
+		if err := v.Validate(); err != nil {
+			p.fail(&ValidationError{Group: gname, Validator: "Validate", Value: value, Err: err})
+		}
+	}
+
+	for _, validator := range p.gvalidators[gname] {
+		if err := validator(value); err != nil {
+			p.fail(&ValidationError{Group: gname, Validator: "RegisterGroupValidator",
+				Value: value, Err: err})
+		}
+	}
+}
+
 // Register a funcation to validate the option corresponding to the field. (Deprecated)
 //
 // The first argument of the function is the tag of the field with string.
@@ -0,0 +1,392 @@
+package argparse
+
+import (
+	"encoding"
+	"errors"
+	"flag"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Convert the string to the value of the type, typ.
+//
+// It supports string, bool, the integers, the floats, time.Duration
+// and the type whose pointer implements encoding.TextUnmarshaler.
+func convertValue(typ reflect.Type, s string) (reflect.Value, error) {
+	if reflect.PtrTo(typ).Implements(textUnmarshalerType) {
+		v := reflect.New(typ)
+		err := v.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+		return v.Elem(), err
+	}
+
+	v := reflect.New(typ).Elem()
+	if typ == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return v, err
+		}
+		v.SetInt(int64(d))
+		return v, nil
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return v, err
+		}
+		v.SetBool(b)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, typ.Bits())
+		if err != nil {
+			return v, err
+		}
+		v.SetFloat(f)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, typ.Bits())
+		if err != nil {
+			return v, err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, typ.Bits())
+		if err != nil {
+			return v, err
+		}
+		v.SetUint(u)
+	default:
+		return v, errors.New(fmt.Sprintf("Don't support the type: %v", typ))
+	}
+	return v, nil
+}
+
+// Return true if the type can be converted by convertValue.
+func canConvert(typ reflect.Type) bool {
+	if typ == durationType || reflect.PtrTo(typ).Implements(textUnmarshalerType) {
+		return true
+	}
+
+	switch typ.Kind() {
+	case reflect.String, reflect.Bool, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// Split s by sep. If sep is empty, s is regarded as only one element.
+func splitValue(s, sep string) []string {
+	if sep == "" {
+		return []string{s}
+	}
+	return strings.Split(s, sep)
+}
+
+// resetter is implemented by the repeatable option, which makes the first
+// setting from the next source replace the current value, not append to it.
+type resetter interface {
+	reset()
+}
+
+// lister is a repeatable option, which returns its elements, or its key-value
+// pairs such as "key=value", as the strings.
+type lister interface {
+	list() []string
+}
+
+// sliceValue is a repeatable option, which appends the value to the slice
+// each time it's set. The first setting will replace the default value.
+type sliceValue struct {
+	sep     string
+	changed bool
+	value   reflect.Value
+	convert func(reflect.Type, string) (reflect.Value, error)
+}
+
+// Return the error failing to set the default value, which is ignored.
+func newSliceValue(typ reflect.Type, sep, _default string,
+	convert func(reflect.Type, string) (reflect.Value, error)) (s *sliceValue, err error) {
+	s = &sliceValue{sep: sep, value: reflect.Zero(typ), convert: convert}
+	if _default != "" {
+		if err = s.Set(_default); err != nil {
+			s.value = reflect.Zero(typ)
+		}
+		s.changed = false
+	}
+	return
+}
+
+func (s *sliceValue) String() string {
+	if !s.value.IsValid() {
+		return ""
+	}
+
+	sep := s.sep
+	if sep == "" {
+		sep = ","
+	}
+
+	return strings.Join(s.list(), sep)
+}
+
+func (s *sliceValue) Set(value string) error {
+	if !s.changed {
+		s.value = reflect.MakeSlice(s.value.Type(), 0, 0)
+		s.changed = true
+	}
+
+	etype := s.value.Type().Elem()
+	for _, v := range splitValue(value, s.sep) {
+		e, err := s.convert(etype, v)
+		if err != nil {
+			return err
+		}
+		s.value = reflect.Append(s.value, e)
+	}
+	return nil
+}
+
+func (s *sliceValue) Get() interface{} {
+	return s.value.Interface()
+}
+
+func (s *sliceValue) reset() {
+	s.changed = false
+}
+
+func (s *sliceValue) list() []string {
+	vs := make([]string, s.value.Len())
+	for i := range vs {
+		vs[i] = fmt.Sprintf("%v", s.value.Index(i).Interface())
+	}
+	return vs
+}
+
+// mapValue is a repeatable option, whose value is the key-value pair, such as
+// "key=value", and which sets the pair into the map each time it's set.
+// The first setting will replace the default value.
+type mapValue struct {
+	sep     string
+	changed bool
+	value   reflect.Value
+	convert func(reflect.Type, string) (reflect.Value, error)
+}
+
+// The default value is a set of the key-value pairs separated by sep,
+// or the comma if sep is empty, such as "k1=v1,k2=v2". Return the error
+// failing to set the default value, which is ignored.
+func newMapValue(typ reflect.Type, sep, _default string,
+	convert func(reflect.Type, string) (reflect.Value, error)) (m *mapValue, err error) {
+	m = &mapValue{sep: sep, value: reflect.Zero(typ), convert: convert}
+	if _default != "" {
+		if sep == "" {
+			m.sep = ","
+		}
+		if err = m.Set(_default); err != nil {
+			m.value = reflect.Zero(typ)
+		}
+		m.sep = sep
+		m.changed = false
+	}
+	return
+}
+
+func (m *mapValue) String() string {
+	if !m.value.IsValid() {
+		return ""
+	}
+
+	sep := m.sep
+	if sep == "" {
+		sep = ","
+	}
+
+	return strings.Join(m.list(), sep)
+}
+
+func (m *mapValue) Set(value string) error {
+	if !m.changed {
+		m.value = reflect.MakeMap(m.value.Type())
+		m.changed = true
+	}
+
+	typ := m.value.Type()
+	for _, kv := range splitValue(value, m.sep) {
+		index := strings.Index(kv, "=")
+		if index < 0 {
+			return errors.New(fmt.Sprintf("The value[%v] is not the format of key=value", kv))
+		}
+
+		key, err := m.convert(typ.Key(), strings.TrimSpace(kv[:index]))
+		if err != nil {
+			return err
+		}
+		v, err := m.convert(typ.Elem(), kv[index+1:])
+		if err != nil {
+			return err
+		}
+		m.value.SetMapIndex(key, v)
+	}
+	return nil
+}
+
+func (m *mapValue) Get() interface{} {
+	return m.value.Interface()
+}
+
+func (m *mapValue) reset() {
+	m.changed = false
+}
+
+func (m *mapValue) list() []string {
+	vs := make([]string, 0, m.value.Len())
+	for _, key := range m.value.MapKeys() {
+		vs = append(vs, fmt.Sprintf("%v=%v", key.Interface(), m.value.MapIndex(key).Interface()))
+	}
+	sort.Strings(vs)
+	return vs
+}
+
+var (
+	flagValueType       = reflect.TypeOf((*flag.Value)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// customValue adapts the type implementing flag.Value or
+// encoding.TextUnmarshaler to flag.Value. flag.Value is preferred.
+type customValue struct {
+	value reflect.Value // The value whose type is the type of the field
+	impl  interface{}   // The implementation of the interface
+}
+
+// Return a new customValue and true if the pointer to typ, or typ itself
+// if it's a pointer, implements flag.Value or encoding.TextUnmarshaler.
+// Or return nil and false.
+func newCustomValue(typ reflect.Type) (*customValue, bool) {
+	var value, ptr reflect.Value
+	if isCustomType(reflect.PtrTo(typ)) {
+		ptr = reflect.New(typ)
+		value = ptr.Elem()
+	} else if typ.Kind() == reflect.Ptr && isCustomType(typ) {
+		ptr = reflect.New(typ.Elem())
+		value = ptr
+	} else {
+		return nil, false
+	}
+	return &customValue{value: value, impl: ptr.Interface()}, true
+}
+
+// Return true if typ implements flag.Value or encoding.TextUnmarshaler.
+func isCustomType(typ reflect.Type) bool {
+	return typ.Implements(flagValueType) || typ.Implements(textUnmarshalerType)
+}
+
+func (c *customValue) String() string {
+	switch v := c.impl.(type) {
+	case nil:
+		return ""
+	case flag.Value:
+		return v.String()
+	case encoding.TextMarshaler:
+		if b, err := v.MarshalText(); err == nil {
+			return string(b)
+		}
+	}
+	return fmt.Sprintf("%v", c.value.Interface())
+}
+
+func (c *customValue) Set(s string) error {
+	if v, ok := c.impl.(flag.Value); ok {
+		return v.Set(s)
+	}
+	return c.impl.(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+}
+
+func (c *customValue) Get() interface{} {
+	return c.value.Interface()
+}
+
+// IsBoolFlag makes it to be used as the bool flag, just like "-flag",
+// if the implementation is a bool flag.
+func (c *customValue) IsBoolFlag() bool {
+	if v, ok := c.impl.(interface {
+		IsBoolFlag() bool
+	}); ok {
+		return v.IsBoolFlag()
+	}
+	return false
+}
+
+// scalarValue is an option whose value is converted by the convert function.
+type scalarValue struct {
+	value   reflect.Value
+	convert func(reflect.Type, string) (reflect.Value, error)
+}
+
+// Return the error failing to set the default value, which is ignored.
+func newScalarValue(typ reflect.Type, _default string,
+	convert func(reflect.Type, string) (reflect.Value, error)) (s *scalarValue, err error) {
+	s = &scalarValue{value: reflect.Zero(typ), convert: convert}
+	if _default != "" {
+		err = s.Set(_default)
+	}
+	return
+}
+
+func (s *scalarValue) String() string {
+	if !s.value.IsValid() {
+		return ""
+	}
+	return fmt.Sprintf("%v", s.value.Interface())
+}
+
+func (s *scalarValue) Set(value string) error {
+	v, err := s.convert(s.value.Type(), value)
+	if err != nil {
+		return err
+	}
+	s.value = v
+	return nil
+}
+
+func (s *scalarValue) Get() interface{} {
+	return s.value.Interface()
+}
+
+// IsBoolFlag makes it to be used as the bool flag, just like "-flag",
+// if the type is bool.
+func (s *scalarValue) IsBoolFlag() bool {
+	return s.value.IsValid() && s.value.Kind() == reflect.Bool
+}
+
+// The prefix of the negated form of the bool option, such as "no_verbose".
+const negPrefix = "no_"
+
+// negatedValue is the negated form of the bool option, which sets the option
+// to the opposite of the given value, such as -no_verbose and -no_verbose=false.
+type negatedValue struct {
+	value flag.Value
+}
+
+func (v negatedValue) String() string {
+	return "false"
+}
+
+func (v negatedValue) Set(s string) error {
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	return v.value.Set(strconv.FormatBool(!b))
+}
+
+func (v negatedValue) IsBoolFlag() bool {
+	return true
+}